@@ -1,25 +1,18 @@
 package zuora
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 )
 
 type accountsService struct {
-	http               Doer
-	authHeaderProvider AuthHeaderProvider
-	baseURL            string
+	client *client
 }
 
-func newAccountsService(http Doer, authHeaderProvider AuthHeaderProvider, baseURL string) *accountsService {
+func newAccountsService(client *client) *accountsService {
 	return &accountsService{
-		http:               http,
-		authHeaderProvider: authHeaderProvider,
-		baseURL:            baseURL,
+		client: client,
 	}
 }
 
@@ -27,73 +20,10 @@ func newAccountsService(http Doer, authHeaderProvider AuthHeaderProvider, baseUR
 // This operation is a quick retrieval that doesn't include the account's subscriptions,
 // invoices, payments, or usage details. Use Get account summary to get more detailed information about an account.
 func (t *accountsService) Get(ctx context.Context, accountKey string) ([]byte, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	url := fmt.Sprintf("%v/v1/accounts/%v", t.baseURL, accountKey)
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
-	}
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	res, err := t.http.Do(req.WithContext(ctx))
-
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
-
-		return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
-	}
-
-	jsonResponse := Response{}
-
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
-	}
-
-	if !jsonResponse.Success {
-		errorResponse := errorResponse{}
-
-		if err := json.Unmarshal(body, &errorResponse); err != nil {
-			return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json error response. Error: %v. Raw JSON: %v", err, string(body))}
-		}
-
-		return nil, errorResponse
-	}
-
-	return body, nil
+	return t.client.do(ctx, request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v1/accounts/%v", accountKey),
+	})
 }
 
 // Summary Retrieves detailed information about the specified customer account.
@@ -104,224 +34,44 @@ func (t *accountsService) Get(ctx context.Context, accountKey string) ([]byte, e
 // limit on the array size.
 // NOTE: Why return a raw array of bytes? You can take advantage of binding to your custom struct with custom properties.
 func (t *accountsService) Summary(ctx context.Context, objectID string) ([]byte, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	url := fmt.Sprintf("%v/v1/accounts/%v/summary", t.baseURL, objectID)
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
-	}
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	res, err := t.http.Do(req.WithContext(ctx))
-
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
-
-		return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
-	}
-
-	jsonResponse := Response{}
-
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
-	}
-
-	if !jsonResponse.Success {
-		errorResponse := errorResponse{}
-
-		if err := json.Unmarshal(body, &errorResponse); err != nil {
-			return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json error response. Error: %v. Raw JSON: %v", err, string(body))}
-		}
-
-		return nil, errorResponse
-	}
-
-	return body, nil
+	return t.client.do(ctx, request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v1/accounts/%v/summary", objectID),
+	})
 }
 
 // Update - Updates a customer account by specifying the account-key.
+// The account is checked first when a Validator was given to WithValidator.
 func (t *accountsService) Update(ctx context.Context, objectID string, account interface{}) (Response, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	url := fmt.Sprintf("%v/v1/accounts/%v", t.baseURL, objectID)
-
-	j, err := json.Marshal(account)
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to convert empty interface: %v", err)}
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(j))
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
-	}
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	res, err := t.http.Do(req.WithContext(ctx))
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return Response{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
-
-		return Response{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
-	}
-
 	jsonResponse := Response{}
 
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
-	}
-
-	if !jsonResponse.Success {
-		errorResponse := errorResponse{}
-
-		if err := json.Unmarshal(body, &errorResponse); err != nil {
-			return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json error response. Error: %v. Raw JSON: %v", err, string(body))}
+	if validator := t.client.validator; validator != nil {
+		if err := validator.validateRESTUpdate(ctx, "Account", account); err != nil {
+			return Response{}, err
 		}
+	}
 
-		return Response{}, errorResponse
+	if err := t.client.doJSON(ctx, request{
+		method: http.MethodPut,
+		path:   fmt.Sprintf("/v1/accounts/%v", objectID),
+		body:   account,
+	}, &jsonResponse); err != nil {
+		return Response{}, err
 	}
 
 	return jsonResponse, nil
 }
 
-// Update - Updates a customer account by specifying the account-key.
+// Create - Creates a customer account.
 func (t *accountsService) Create(ctx context.Context, account interface{}) (Response, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	url := fmt.Sprintf("%v/v1/accounts", t.baseURL)
-
-	j, err := json.Marshal(account)
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to convert empty interface: %v", err)}
-	}
-
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(j))
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
-	}
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	res, err := t.http.Do(req.WithContext(ctx))
-
-	if err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return Response{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
-
-		return Response{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
-	}
-
 	jsonResponse := Response{}
 
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
-	}
-
-	if !jsonResponse.Success {
-		errorResponse := errorResponse{}
-
-		if err := json.Unmarshal(body, &errorResponse); err != nil {
-			return Response{}, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json error response. Error: %v. Raw JSON: %v", err, string(body))}
-		}
-
-		return Response{}, errorResponse
+	if err := t.client.doJSON(ctx, request{
+		method: http.MethodPost,
+		path:   "/v1/accounts",
+		body:   account,
+	}, &jsonResponse); err != nil {
+		return Response{}, err
 	}
 
 	return jsonResponse, nil
@@ -4,25 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strings"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+const (
+	// actionBatchSize is the number of objects most actions accept in a single call.
+	actionBatchSize = 50
+	// amendBatchSize is the number of requests amend accepts in a single call.
+	amendBatchSize = 10
 )
 
 type actionsService struct {
-	http               Doer
-	authHeaderProvider AuthHeaderProvider
-	baseURL            string
-	isPce              bool
+	client *client
 }
 
-func newActionsService(http Doer, authHeaderProvider AuthHeaderProvider, baseURL string, isPce bool) *actionsService {
+func newActionsService(client *client) *actionsService {
 	return &actionsService{
-		http:               http,
-		authHeaderProvider: authHeaderProvider,
-		baseURL:            baseURL,
-		isPce:              isPce,
+		client: client,
 	}
 }
 
@@ -30,7 +33,7 @@ func newActionsService(http Doer, authHeaderProvider AuthHeaderProvider, baseURL
 // the fields to retrieve from that object, and any filters to determine whether a
 // given object should be queried.
 // You can use Zuora Object Query Language (ZOQL) to construct those queries,
-// passing them through the queryString.
+// either with the QueryBuilder returned by Select or as a raw ZOQL string.
 // https://knowledgecenter.zuora.com/DC_Developers/K_Zuora_Object_Query_Language
 // Once the call is made, the API executes the query against the specified object and
 // returns a query response object to your application. Your application can then iterate
@@ -46,79 +49,82 @@ func newActionsService(http Doer, authHeaderProvider AuthHeaderProvider, baseURL
 // * The Invoice Settlement feature is not supported. This feature includes Unapplied Payments, Credit and Debit Memo, and Invoice Item Settlement. The Orders feature is also not supported.
 //
 // *The default WSDL version for Actions is 79.
-func (t *actionsService) Query(ctx context.Context, zoqlQuery string) ([]byte, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
+//
+// The result holds up to 2000 records, decode them with QueryResult.Decode.
+func (t *actionsService) Query(ctx context.Context, querier Querier) (*QueryResult, error) {
+	queryString, err := buildQuery(querier)
 
 	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
+		return nil, err
 	}
 
-	var url string
+	payload := queryRequest{QueryString: queryString}
 
-	if t.isPce {
-		url = fmt.Sprintf("%v:19016/v1/action/query", t.baseURL)
-	} else {
-		url = fmt.Sprintf("%v/v1/action/query", t.baseURL)
+	if batchSizer, ok := querier.(interface{ BatchSize() int }); ok && batchSizer.BatchSize() > 0 {
+		payload.Conf = &queryConf{BatchSize: batchSizer.BatchSize()}
 	}
 
-	var buffer bytes.Buffer
-	buffer.WriteString(`{ "queryString" : "`)
-	buffer.WriteString(strings.TrimSpace(zoqlQuery))
-	buffer.WriteString(`"}`)
-
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(buffer.String()))
+	result := &QueryResult{}
 
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
+	if err := t.client.doJSON(ctx, request{method: http.MethodPost, path: "/v1/action/query", body: payload, idempotent: true}, result); err != nil {
+		return nil, err
 	}
 
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
+	return result, nil
+}
 
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
+// QueryMore returns the next records of a query whose result was not done,
+// using the QueryLocator of the previous QueryResult.
+// https://www.zuora.com/developer/api-reference/#operation/Action_POSTqueryMore
+func (t *actionsService) QueryMore(ctx context.Context, queryLocator string) (*QueryResult, error) {
+	return t.queryMore(ctx, queryLocator, 0)
+}
 
-	res, err := t.http.Do(req.WithContext(ctx))
+func (t *actionsService) queryMore(ctx context.Context, queryLocator string, batchSize int) (*QueryResult, error) {
+	payload := queryMoreRequest{QueryLocator: queryLocator}
 
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
+	if batchSize > 0 {
+		payload.Conf = &queryConf{BatchSize: batchSize}
 	}
 
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
+	result := &QueryResult{}
 
-		return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
+	if err := t.client.doJSON(ctx, request{method: http.MethodPost, path: "/v1/action/queryMore", body: payload, idempotent: true}, result); err != nil {
+		return nil, err
 	}
 
-	return body, nil
+	return result, nil
+}
+
+// QueryAll runs the query and returns an iterator over every record, following the query
+// locator with QueryMore past the 2000 records limit. Records are fetched one page at a time
+// as the iteration goes, stop early by no longer calling Next.
+//
+//	records := zuoraAPI.V1.ActionsService.QueryAll(ctx, query)
+//
+//	for records.Next() {
+//		invoice := Invoice{}
+//
+//		if err := records.Decode(&invoice); err != nil {
+//			return err
+//		}
+//	}
+//
+//	if err := records.Err(); err != nil {
+//		return err
+//	}
+func (t *actionsService) QueryAll(ctx context.Context, querier Querier) *QueryIterator {
+	return &QueryIterator{ctx: ctx, actions: t, querier: querier}
 }
 
-// Query The create call can be used to create zObjects in bulk.
+// Create The create call can be used to create zObjects in bulk.
 // API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTcreate
 //
 // The useSingleTransaction param controls how the objects are created. If set to false (the default API behavior),
 // each object is created in its own unit of work. In addition, the response will indicate which objects were created and which ones where not.
 // If the parameter is set to true, then all of the objects will be created in the same unit of work. This means that either all of the objects will be created, or none of them will.
 //
-// An example of when useSingleTransaction is required is when creating InvoiceItemAdjustment objects where one is a Credit and one is a Charge
+// # An example of when useSingleTransaction is required is when creating InvoiceItemAdjustment objects where one is a Credit and one is a Charge
 //
 // Limitations
 // This call has the following limitations:
@@ -132,65 +138,324 @@ func (t *actionsService) Query(ctx context.Context, zoqlQuery string) ([]byte, e
 // * The default WSDL version for Actions is 79.
 //
 // * The Invoice Settlement feature is not supported. This feature includes Unapplied Payments, Credit and Debit Memo, and Invoice Item Settlement. The Orders feature is also not supported.
+//
+// When any of the objects could not be created, the raw response is returned together with an *Error
+// listing the errors of every failed object. CreateObjects lifts the 50 objects limit and decodes the results.
+//
+// With WithValidator, the objects of the payload are validated before it is sent.
 func (t *actionsService) Create(ctx context.Context, actionPayload interface{}, useSingleTransaction bool) ([]byte, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
+	if t.client.validator != nil {
+		payload := struct {
+			Objects json.RawMessage `json:"objects"`
+			Type    string          `json:"type"`
+		}{}
+
+		if data, err := json.Marshal(actionPayload); err == nil && json.Unmarshal(data, &payload) == nil && payload.Type != "" && len(payload.Objects) > 0 {
+			if err := t.client.validator.ValidateCreate(ctx, ObjecName(payload.Type), payload.Objects); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	path := "/v1/action/create"
+	if useSingleTransaction {
+		path += "?useSingleTransaction=true"
+	}
+
+	return t.client.do(ctx, request{
+		method: http.MethodPost,
+		path:   path,
+		body:   actionPayload,
+	})
+}
+
+// CreateObjects creates objects, a slice of zObjects of type objectType such as Account, in bulk.
+// Unlike Create, any number of objects can be given: they are sent 50 at a time, with as many
+// calls in flight as set by WithActionConcurrency.
+//
+// One SaveResult is returned per object, in the same order. Objects Zuora refused are reported
+// through their SaveResult, not through the error, which is only set when a call failed as a whole.
+// In that case, the results of the objects whose call did not succeed have no ID and Success is false.
+//
+// With useSingleTransaction, all of the objects are created or none of them is, so more than 50
+// objects are refused before anything is sent.
+func (t *actionsService) CreateObjects(ctx context.Context, objectType string, objects interface{}, useSingleTransaction bool) ([]SaveResult, error) {
+	return t.saveObjects(ctx, "create", objectType, objects, useSingleTransaction)
+}
+
+// Update updates objects, a slice of zObjects of type objectType that all set their Id, in bulk.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTupdate
+//
+// Objects are sent and their results returned as in CreateObjects. With WithValidator, both
+// CreateObjects and Update validate every object before sending any of them.
+func (t *actionsService) Update(ctx context.Context, objectType string, objects interface{}, useSingleTransaction bool) ([]SaveResult, error) {
+	return t.saveObjects(ctx, "update", objectType, objects, useSingleTransaction)
+}
+
+// Delete deletes the objects of type objectType identified by ids, in bulk.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTdelete
+//
+// IDs are sent and their results returned as in CreateObjects.
+func (t *actionsService) Delete(ctx context.Context, objectType string, ids []string, useSingleTransaction bool) ([]SaveResult, error) {
+	return t.saveResults(ctx, "delete", len(ids), useSingleTransaction, func(start, end int) interface{} {
+		return idsRequest{IDs: ids[start:end], Type: objectType}
+	})
+}
+
+// Generate generates invoices, a slice of Invoice zObjects setting at least AccountId,
+// InvoiceDate and TargetDate, for example for bulk invoice generation.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTgenerate
+//
+// Invoices are sent and their results returned as in CreateObjects.
+func (t *actionsService) Generate(ctx context.Context, invoices interface{}) ([]SaveResult, error) {
+	return t.saveObjects(ctx, "generate", "Invoice", invoices, false)
+}
+
+// Execute executes the objects of type objectType identified by ids, such as InvoiceSplit.
+// When synchronous is false, Zuora returns before they are executed.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTexecute
+//
+// IDs are sent and their results returned as in CreateObjects.
+func (t *actionsService) Execute(ctx context.Context, objectType string, ids []string, synchronous bool) ([]SaveResult, error) {
+	return t.saveResults(ctx, "execute", len(ids), false, func(start, end int) interface{} {
+		return executeRequest{IDs: ids[start:end], Type: objectType, Synchronous: synchronous}
+	})
+}
+
+// Subscribe creates accounts and their subscriptions in a single call per subscription.
+// subscribes is a slice of subscribe requests, each setting Account, SubscriptionData and
+// optionally BillToContact, PaymentMethod or SubscribeOptions.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTsubscribe
+//
+// Requests are sent 50 at a time and one SubscribeResult is returned per request, in the same
+// order, as in CreateObjects.
+func (t *actionsService) Subscribe(ctx context.Context, subscribes interface{}) ([]SubscribeResult, error) {
+	items, err := actionObjects(subscribes)
 
 	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/v1/action/create", t.baseURL)
-	if useSingleTransaction {
-		url += "?useSingleTransaction=true"
+	results := make([]SubscribeResult, len(items))
+	for i := range results {
+		results[i].Index = i
 	}
 
-	j, err := json.Marshal(actionPayload)
+	err = t.inBatches(ctx, "subscribe", len(items), actionBatchSize, false, func(ctx context.Context, path string, start, end int) error {
+		batch := []SubscribeResult{}
+
+		if err := t.post(ctx, path, subscribeRequest{Subscribes: items[start:end]}, &batch, end-start, func() int { return len(batch) }); err != nil {
+			return err
+		}
+
+		for i := range batch {
+			batch[i].Index = start + i
+			results[start+i] = batch[i]
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// Amend amends subscriptions. requests is a slice of amend requests, each setting
+// Amendments and optionally AmendOptions or PreviewOptions.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/Action_POSTamend
+//
+// Requests are sent 10 at a time and one AmendResult is returned per request, in the same
+// order, as in CreateObjects.
+func (t *actionsService) Amend(ctx context.Context, requests interface{}) ([]AmendResult, error) {
+	items, err := actionObjects(requests)
 
 	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to convert empty interface: %v", err)}
+		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(j))
+	results := make([]AmendResult, len(items))
+	for i := range results {
+		results[i].Index = i
+	}
+
+	err = t.inBatches(ctx, "amend", len(items), amendBatchSize, false, func(ctx context.Context, path string, start, end int) error {
+		response := amendResponse{}
+
+		if err := t.post(ctx, path, amendRequest{Requests: items[start:end]}, &response, end-start, func() int { return len(response.Results) }); err != nil {
+			return err
+		}
+
+		for i := range response.Results {
+			response.Results[i].Index = start + i
+			results[start+i] = response.Results[i]
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// saveObjects sends objects of type objectType to an action returning a SaveResult per object.
+func (t *actionsService) saveObjects(ctx context.Context, action, objectType string, objects interface{}, useSingleTransaction bool) ([]SaveResult, error) {
+	items, err := actionObjects(objects)
 
 	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
+		return nil, err
 	}
 
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
+	if validator := t.client.validator; validator != nil && (action == "create" || action == "update") {
+		validate := validator.ValidateCreate
+		if action == "update" {
+			validate = validator.ValidateUpdate
+		}
 
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
+		if err := validate(ctx, ObjecName(objectType), items); err != nil {
+			return nil, err
+		}
 	}
 
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
+	return t.saveResults(ctx, action, len(items), useSingleTransaction, func(start, end int) interface{} {
+		return objectsRequest{Objects: items[start:end], Type: objectType}
+	})
+}
+
+// saveResults sends count objects to an action, in batches whose body is built by payload, and merges the results.
+func (t *actionsService) saveResults(ctx context.Context, action string, count int, useSingleTransaction bool, payload func(start, end int) interface{}) ([]SaveResult, error) {
+	results := make([]SaveResult, count)
+	for i := range results {
+		results[i].Index = i
 	}
 
-	res, err := t.http.Do(req.WithContext(ctx))
+	err := t.inBatches(ctx, action, count, actionBatchSize, useSingleTransaction, func(ctx context.Context, path string, start, end int) error {
+		batch := []SaveResult{}
 
-	if err != nil {
-		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
+		if err := t.post(ctx, path, payload(start, end), &batch, end-start, func() int { return len(batch) }); err != nil {
+			return err
+		}
+
+		for i := range batch {
+			batch[i].Index = start + i
+			results[start+i] = batch[i]
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// inBatches calls send for every batch of at most batchSize of the count objects given to action,
+// with as many calls in flight as the client action concurrency. The first failure cancels the
+// batches not sent yet and is returned. When there are several batches, an idempotency key set
+// on ctx is suffixed with the batch number, so Zuora does not take a batch for a replay of another.
+func (t *actionsService) inBatches(ctx context.Context, action string, count, batchSize int, useSingleTransaction bool, send func(ctx context.Context, path string, start, end int) error) error {
+	if useSingleTransaction && count > batchSize {
+		return responseError{isTemporary: false, message: fmt.Sprintf("%v objects cannot be sent to %v in a single transaction, the limit is %v", count, action, batchSize)}
+	}
+
+	path := "/v1/action/" + action
+	if useSingleTransaction {
+		path += "?useSingleTransaction=true"
+	}
+
+	concurrency := t.client.actionConcurrency
+	if concurrency < 1 {
+		concurrency = 1
 	}
 
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		slots    = make(chan struct{}, concurrency)
+	)
 
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
+	for start := 0; start < count && batchCtx.Err() == nil; start += batchSize {
+		end := start + batchSize
+		if end > count {
+			end = count
 		}
 
-		if err != nil {
-			return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
+		select {
+		case slots <- struct{}{}:
+		case <-batchCtx.Done():
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			ctx := batchCtx
+			if key, ok := idempotencyKey(ctx); ok && count > batchSize {
+				ctx = WithCallOptions(ctx, CallIdempotencyKey(key+"-"+strconv.Itoa(start/batchSize)))
+			}
+
+			if err := send(ctx, path, start, end); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+
+	return firstErr
+}
+
+// post sends a single batch of want objects and unmarshals the response into out.
+// got returns the number of results out holds once unmarshalled.
+func (t *actionsService) post(ctx context.Context, path string, payload interface{}, out interface{}, want int, got func() int) error {
+	body, err := t.client.do(ctx, request{method: http.MethodPost, path: path, body: payload})
+
+	// Objects Zuora refused are reported as an *Error along with the array of results.
+	zuoraErr := &Error{}
+	if err != nil && !(errors.As(err, &zuoraErr) && bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))) {
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal action results. Error: %v. JSON: %v", err, string(body)), err: err}
+	}
+
+	if got() != want {
+		return responseError{isTemporary: false, message: fmt.Sprintf("expected %v action results, got %v. JSON: %v", want, got(), string(body))}
+	}
+
+	return nil
+}
+
+// actionObjects turns objects, a slice or an array, into the list of objects sent to an action.
+func actionObjects(objects interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(objects)
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("actions need a slice of objects, got %T", objects)}
+	}
+
+	items := make([]interface{}, v.Len())
+
+	for i := range items {
+		item := v.Index(i)
+
+		if (item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface || item.Kind() == reflect.Map) && item.IsNil() {
+			return nil, responseError{isTemporary: false, message: fmt.Sprintf("object %v is nil, zObjects can not be null", i)}
 		}
 
-		return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
+		items[i] = item.Interface()
 	}
 
-	return body, nil
+	return items, nil
 }
@@ -0,0 +1,294 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestActionsCreateObjectsBatches(t *testing.T) {
+	var inFlight, maxInFlight, calls int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if req.URL.Path != "/v1/action/create" || req.URL.Query().Get("useSingleTransaction") != "" {
+			t.Errorf("unexpected request to %v", req.URL)
+		}
+
+		payload := struct {
+			Objects []map[string]string `json:"objects"`
+			Type    string              `json:"type"`
+		}{}
+		json.NewDecoder(req.Body).Decode(&payload)
+
+		if payload.Type != "Account" || len(payload.Objects) == 0 || len(payload.Objects) > 50 {
+			t.Errorf("create payload has type %q and %v objects", payload.Type, len(payload.Objects))
+		}
+
+		results := []map[string]interface{}{}
+
+		for _, object := range payload.Objects {
+			if object["Name"] == "bad" {
+				results = append(results, map[string]interface{}{"Success": false, "Errors": []ObjectError{{Code: "MISSING_REQUIRED_VALUE", Message: "Currency is required"}}})
+				continue
+			}
+
+			results = append(results, map[string]interface{}{"Success": true, "Id": "id-" + object["Name"]})
+		}
+
+		json.NewEncoder(rw).Encode(results)
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithActionConcurrency(2))
+
+	accounts := []map[string]string{}
+	for i := 0; i < 120; i++ {
+		accounts = append(accounts, map[string]string{"Name": fmt.Sprint(i)})
+	}
+	accounts[75]["Name"] = "bad"
+
+	results, err := api.V1.ActionsService.CreateObjects(context.Background(), "Account", accounts, false)
+	if err != nil {
+		t.Fatalf("ActionsService.CreateObjects() = %v", err)
+	}
+
+	if len(results) != 120 || calls != 3 {
+		t.Fatalf("ActionsService.CreateObjects() returned %v results in %v calls, want 120 in 3", len(results), calls)
+	}
+
+	if maxInFlight > 2 {
+		t.Errorf("ActionsService.CreateObjects() sent %v batches at once, want at most 2", maxInFlight)
+	}
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("results[%v].Index = %v", i, result.Index)
+		}
+
+		if i == 75 {
+			if result.Success || len(result.Errors) != 1 || result.Errors[0].Code != "MISSING_REQUIRED_VALUE" {
+				t.Errorf("results[75] = %+v, want the object errors", result)
+			}
+			continue
+		}
+
+		if !result.Success || result.ID != fmt.Sprintf("id-%v", i) {
+			t.Errorf("results[%v] = %+v", i, result)
+		}
+	}
+}
+
+func TestActionsCreateObjectsSingleTransaction(t *testing.T) {
+	calls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		if req.URL.Query().Get("useSingleTransaction") != "true" {
+			t.Errorf("useSingleTransaction = %q, want true", req.URL.Query().Get("useSingleTransaction"))
+		}
+
+		rw.Write([]byte(`[{"Success": true, "Id": "1"}, {"Success": true, "Id": "2"}]`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	ctx := context.Background()
+
+	results, err := api.V1.ActionsService.CreateObjects(ctx, "InvoiceItemAdjustment", []map[string]string{{"Type": "Credit"}, {"Type": "Charge"}}, true)
+	if err != nil || len(results) != 2 || results[1].ID != "2" {
+		t.Errorf("ActionsService.CreateObjects() = %+v, %v", results, err)
+	}
+
+	adjustments := []map[string]string{}
+	for i := 0; i < 51; i++ {
+		adjustments = append(adjustments, map[string]string{"Type": "Credit"})
+	}
+
+	if _, err := api.V1.ActionsService.CreateObjects(ctx, "InvoiceItemAdjustment", adjustments, true); err == nil {
+		t.Errorf("ActionsService.CreateObjects() should refuse more than 50 objects in a single transaction")
+	}
+
+	if calls != 1 {
+		t.Errorf("ActionsService.CreateObjects() made %v calls, want 1", calls)
+	}
+}
+
+func TestActionsIdempotencyKeyPerBatch(t *testing.T) {
+	keys := make(chan string, 10)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		keys <- req.Header.Get("Idempotency-Key")
+
+		payload := objectsRequest{}
+		json.NewDecoder(req.Body).Decode(&payload)
+
+		results := []SaveResult{}
+		for range payload.Objects {
+			results = append(results, SaveResult{Success: true, ID: "1"})
+		}
+
+		json.NewEncoder(rw).Encode(results)
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithActionConcurrency(1))
+	accounts := []map[string]string{}
+	for i := 0; i < 100; i++ {
+		accounts = append(accounts, map[string]string{"Name": fmt.Sprint(i)})
+	}
+
+	for _, ctx := range []context.Context{
+		WithCallOptions(context.Background(), CallIdempotencyKey("k1")),
+		context.WithValue(context.Background(), ContextKeyIdempotencyKey, "k1"),
+	} {
+		if _, err := api.V1.ActionsService.CreateObjects(ctx, "Account", accounts, false); err != nil {
+			t.Fatalf("ActionsService.CreateObjects() = %v", err)
+		}
+
+		if got := []string{<-keys, <-keys}; got[0] != "k1-0" || got[1] != "k1-1" {
+			t.Errorf("ActionsService.CreateObjects() sent the idempotency keys %v, want [k1-0 k1-1]", got)
+		}
+	}
+
+	ctx := WithCallOptions(context.Background(), CallIdempotencyKey("k2"))
+	if _, err := api.V1.ActionsService.CreateObjects(ctx, "Account", accounts[:50], false); err != nil {
+		t.Fatalf("ActionsService.CreateObjects() = %v", err)
+	}
+
+	if got := <-keys; got != "k2" {
+		t.Errorf("ActionsService.CreateObjects() sent the idempotency key %q for a single batch, want k2", got)
+	}
+}
+
+func TestActionsCreateObjectsFailedCall(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"faultcode": "fns:INVALID_TYPE", "faultstring": "invalid type for create"}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+
+	results, err := api.V1.ActionsService.CreateObjects(context.Background(), "Nope", []map[string]string{{"Name": "a"}}, false)
+
+	if _, ok := err.(*Error); !ok {
+		t.Errorf("ActionsService.CreateObjects() = %v, want an *Error", err)
+	}
+
+	if len(results) != 1 || results[0].Success {
+		t.Errorf("ActionsService.CreateObjects() = %+v, want an unsaved result", results)
+	}
+
+	if _, err := api.V1.ActionsService.CreateObjects(context.Background(), "Account", "not a slice", false); err == nil {
+		t.Errorf("ActionsService.CreateObjects() should refuse a value that is not a slice")
+	}
+}
+
+func TestActionsEndpoints(t *testing.T) {
+	var amendCalls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload := map[string]json.RawMessage{}
+		json.NewDecoder(req.Body).Decode(&payload)
+
+		switch req.URL.Path {
+		case "/v1/action/update":
+			if string(payload["type"]) != `"Account"` || req.URL.Query().Get("useSingleTransaction") != "true" {
+				t.Errorf("update payload = %s, query = %v", payload, req.URL.RawQuery)
+			}
+
+			rw.Write([]byte(`[{"Success": true, "Id": "A1"}]`))
+		case "/v1/action/delete":
+			if string(payload["ids"]) != `["A1","A2"]` || string(payload["type"]) != `"Account"` {
+				t.Errorf("delete payload = %s", payload)
+			}
+
+			rw.Write([]byte(`[{"id": "A1", "success": true}, {"id": "A2", "success": false, "errors": [{"Code": "INVALID_ID", "Message": "invalid id"}]}]`))
+		case "/v1/action/generate":
+			if string(payload["type"]) != `"Invoice"` {
+				t.Errorf("generate payload = %s", payload)
+			}
+
+			rw.Write([]byte(`[{"Success": true, "Id": "I1"}]`))
+		case "/v1/action/execute":
+			if string(payload["ids"]) != `["S1"]` || string(payload["type"]) != `"InvoiceSplit"` || string(payload["synchronous"]) != "true" {
+				t.Errorf("execute payload = %s", payload)
+			}
+
+			rw.Write([]byte(`[{"Success": true, "Id": "S1"}]`))
+		case "/v1/action/subscribe":
+			rw.Write([]byte(`[{"Success": true, "AccountId": "A1", "SubscriptionNumber": "SUB-1", "TotalMrr": 10.5}]`))
+		case "/v1/action/amend":
+			call := atomic.AddInt32(&amendCalls, 1)
+			requests := []json.RawMessage{}
+			json.Unmarshal(payload["requests"], &requests)
+
+			results := []AmendResult{}
+			for range requests {
+				results = append(results, AmendResult{Success: true, SubscriptionID: fmt.Sprint("S", call)})
+			}
+
+			json.NewEncoder(rw).Encode(amendResponse{Results: results})
+		default:
+			t.Errorf("unexpected request to %v", req.URL)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	ctx := context.Background()
+
+	if results, err := api.V1.ActionsService.Update(ctx, "Account", []map[string]string{{"Id": "A1", "Name": "Acme"}}, true); err != nil || len(results) != 1 || !results[0].Success {
+		t.Errorf("ActionsService.Update() = %+v, %v", results, err)
+	}
+
+	results, err := api.V1.ActionsService.Delete(ctx, "Account", []string{"A1", "A2"}, false)
+	if err != nil || len(results) != 2 || !results[0].Success || results[1].Success || results[1].ID != "A2" || len(results[1].Errors) != 1 {
+		t.Errorf("ActionsService.Delete() = %+v, %v", results, err)
+	}
+
+	if results, err := api.V1.ActionsService.Generate(ctx, []map[string]string{{"AccountId": "A1"}}); err != nil || len(results) != 1 || results[0].ID != "I1" {
+		t.Errorf("ActionsService.Generate() = %+v, %v", results, err)
+	}
+
+	if results, err := api.V1.ActionsService.Execute(ctx, "InvoiceSplit", []string{"S1"}, true); err != nil || len(results) != 1 || results[0].ID != "S1" {
+		t.Errorf("ActionsService.Execute() = %+v, %v", results, err)
+	}
+
+	subscribes, err := api.V1.ActionsService.Subscribe(ctx, []map[string]interface{}{{"Account": map[string]string{"Name": "Acme"}}})
+	if err != nil || len(subscribes) != 1 || subscribes[0].SubscriptionNumber != "SUB-1" || subscribes[0].TotalMrr != 10.5 {
+		t.Errorf("ActionsService.Subscribe() = %+v, %v", subscribes, err)
+	}
+
+	requests := make([]map[string]interface{}, 12)
+	for i := range requests {
+		requests[i] = map[string]interface{}{"Amendments": []map[string]string{{"Type": "Renewal"}}}
+	}
+
+	amends, err := api.V1.ActionsService.Amend(ctx, requests)
+	if err != nil || len(amends) != 12 || amendCalls != 2 {
+		t.Fatalf("ActionsService.Amend() = %v results, %v in %v calls, want 12 results in 2 calls", len(amends), err, amendCalls)
+	}
+
+	for i, amend := range amends {
+		if amend.Index != i || !amend.Success || amend.SubscriptionID == "" {
+			t.Errorf("amends[%v] = %+v", i, amend)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type queryRequest struct {
+	QueryString string     `json:"queryString"`
+	Conf        *queryConf `json:"conf,omitempty"`
+}
+
+type queryMoreRequest struct {
+	QueryLocator string     `json:"queryLocator"`
+	Conf         *queryConf `json:"conf,omitempty"`
+}
+
+type queryConf struct {
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+type objectsRequest struct {
+	Objects []interface{} `json:"objects"`
+	Type    string        `json:"type"`
+}
+
+type idsRequest struct {
+	IDs  []string `json:"ids"`
+	Type string   `json:"type"`
+}
+
+type executeRequest struct {
+	IDs         []string `json:"ids"`
+	Type        string   `json:"type"`
+	Synchronous bool     `json:"synchronous"`
+}
+
+type subscribeRequest struct {
+	Subscribes []interface{} `json:"subscribes"`
+}
+
+type amendRequest struct {
+	Requests []interface{} `json:"requests"`
+}
+
+type amendResponse struct {
+	Results []AmendResult `json:"results"`
+}
+
+// SaveResult is the outcome of saving a single object through an action such as CreateObjects.
+// Index is the position of the object in the slice given to the action.
+type SaveResult struct {
+	Index   int           `json:"-"`
+	ID      string        `json:"Id"`
+	Success bool          `json:"Success"`
+	Errors  []ObjectError `json:"Errors"`
+}
+
+// SubscribeResult is the outcome of a single subscribe request, see ActionsService.Subscribe.
+// Index is the position of the request in the slice given to Subscribe.
+type SubscribeResult struct {
+	Index                    int           `json:"-"`
+	Success                  bool          `json:"Success"`
+	AccountID                string        `json:"AccountId"`
+	AccountNumber            string        `json:"AccountNumber"`
+	SubscriptionID           string        `json:"SubscriptionId"`
+	SubscriptionNumber       string        `json:"SubscriptionNumber"`
+	InvoiceID                string        `json:"InvoiceId"`
+	InvoiceNumber            string        `json:"InvoiceNumber"`
+	PaymentTransactionNumber string        `json:"PaymentTransactionNumber"`
+	TotalMrr                 float64       `json:"TotalMrr"`
+	TotalTcv                 float64       `json:"TotalTcv"`
+	Errors                   []ObjectError `json:"Errors"`
+}
+
+// AmendResult is the outcome of a single amend request, see ActionsService.Amend.
+// Index is the position of the request in the slice given to Amend.
+type AmendResult struct {
+	Index                    int           `json:"-"`
+	Success                  bool          `json:"Success"`
+	SubscriptionID           string        `json:"SubscriptionId"`
+	AmendmentIDs             []string      `json:"AmendmentIds"`
+	InvoiceID                string        `json:"InvoiceId"`
+	PaymentTransactionNumber string        `json:"PaymentTransactionNumber"`
+	TotalDeltaMrr            float64       `json:"TotalDeltaMrr"`
+	TotalDeltaTcv            float64       `json:"TotalDeltaTcv"`
+	Errors                   []ObjectError `json:"Errors"`
+}
+
+// QueryResult is the response of a ZOQL query. When Done is false, more records
+// are available through QueryLocator.
+type QueryResult struct {
+	Size         int    `json:"size"`
+	Done         bool   `json:"done"`
+	QueryLocator string `json:"queryLocator"`
+	records      json.RawMessage
+}
+
+// UnmarshalJSON keeps the records aside so they can be decoded by Decode.
+func (r *QueryResult) UnmarshalJSON(data []byte) error {
+	type queryResult QueryResult
+	raw := struct {
+		*queryResult
+		Records json.RawMessage `json:"records"`
+	}{queryResult: (*queryResult)(r)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.records = raw.Records
+	return nil
+}
+
+// Decode unmarshals the records into out, a pointer to a slice of structs tagged with
+// the field names returned by Zuora, such as `json:"Id"`, or to a []map[string]interface{}.
+func (r *QueryResult) Decode(out interface{}) error {
+	records := r.records
+
+	if len(records) == 0 || string(records) == "null" {
+		records = json.RawMessage("[]")
+	}
+
+	if err := json.Unmarshal(records, out); err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal query records. Error: %v. JSON: %v", err, string(records)), err: err}
+	}
+
+	return nil
+}
+
+// Maps returns the records as generic maps.
+func (r *QueryResult) Maps() ([]map[string]interface{}, error) {
+	records := []map[string]interface{}{}
+
+	if err := r.Decode(&records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// QueryIterator walks the records of a query page by page, see ActionsService.QueryAll.
+type QueryIterator struct {
+	ctx     context.Context
+	actions *actionsService
+	querier Querier
+
+	result  *QueryResult
+	records []json.RawMessage
+	current json.RawMessage
+	read    int
+	err     error
+}
+
+// Next moves to the next record, fetching the next page when needed. It returns false
+// once every record, or the Limit of the query, was read or an error happened, see Err.
+func (it *QueryIterator) Next() bool {
+	if limit := it.limit(); limit > 0 && it.read >= limit {
+		return false
+	}
+
+	for len(it.records) == 0 {
+		if it.err != nil || (it.result != nil && (it.result.Done || it.result.QueryLocator == "")) {
+			return false
+		}
+
+		if it.result == nil {
+			it.result, it.err = it.actions.Query(it.ctx, it.querier)
+		} else {
+			it.result, it.err = it.actions.queryMore(it.ctx, it.result.QueryLocator, it.batchSize())
+		}
+
+		if it.err != nil {
+			return false
+		}
+
+		it.records = nil
+		it.err = it.result.Decode(&it.records)
+	}
+
+	it.current, it.records = it.records[0], it.records[1:]
+	it.read++
+	return true
+}
+
+// Decode unmarshals the current record into out, a struct tagged with the field names
+// returned by Zuora or a map[string]interface{}.
+func (it *QueryIterator) Decode(out interface{}) error {
+	if err := json.Unmarshal(it.current, out); err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal query record. Error: %v. JSON: %v", err, string(it.current)), err: err}
+	}
+
+	return nil
+}
+
+// Result returns the page the current record belongs to.
+func (it *QueryIterator) Result() *QueryResult {
+	return it.result
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *QueryIterator) Err() error {
+	return it.err
+}
+
+// batchSize is the number of records the next page asks for, no more than are left to read.
+func (it *QueryIterator) batchSize() int {
+	size := 0
+
+	if batchSizer, ok := it.querier.(interface{ BatchSize() int }); ok {
+		size = batchSizer.BatchSize()
+	}
+
+	if limit := it.limit(); limit > 0 && limit-it.read < size {
+		size = limit - it.read
+	}
+
+	return size
+}
+
+func (it *QueryIterator) limit() int {
+	if limiter, ok := it.querier.(interface{ recordLimit() int }); ok {
+		return limiter.recordLimit()
+	}
+
+	return 0
+}
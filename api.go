@@ -10,6 +10,9 @@ type V1 struct {
 	PaymentMethods       *paymentMethods
 	Invoices             *invoices
 	RefundService        *refundService
+	ExportsService       *exportsService
+	BatchQueryService    *batchQueryService
+	DataQueryService     *dataQueryService
 }
 
 //API is a container struct with access to all underlying services
@@ -18,35 +21,41 @@ type API struct {
 	ObjectModel ObjectModel
 }
 
-//NewAPI helper function to create all required services to interact with Zuora
-func NewAPI(httpClient Doer, authHeaderProvider AuthHeaderProvider, baseURL string) *API {
-	return &API{
-		V1: V1{
-			AccountsService:      newAccountsService(httpClient, authHeaderProvider, baseURL),
-			CatalogService:       newCatalogService(httpClient, authHeaderProvider, baseURL),
-			SubscriptionsService: newSubscriptionsService(httpClient, authHeaderProvider, baseURL),
-			DescribeService:      newDescribeService(httpClient, authHeaderProvider, baseURL),
-			ActionsService:       newActionsService(httpClient, authHeaderProvider, baseURL, false),
-			PaymentMethods:       newPaymentMethods(httpClient, authHeaderProvider, baseURL, false),
-			Invoices:             newInvoices(httpClient, authHeaderProvider, baseURL, false),
-			RefundService:        newRefundService(httpClient, authHeaderProvider, baseURL, false),
-		},
-		ObjectModel: newObjectModel(),
-	}
+//NewAPI helper function to create all required services to interact with Zuora.
+//
+//	zuoraAPI := zuora.NewAPI(
+//		zuora.WithBaseURL("https://rest.apisandbox.zuora.com"),
+//		zuora.WithOAuth(clientID, clientSecret),
+//		zuora.WithRetryPolicy(zuora.DefaultRetryPolicy()),
+//	)
+func NewAPI(options ...ConfigOption) *API {
+	return newAPI(newClient(newConfig(options...)))
+}
+
+//NewPCEAPI helper function to create all required services to interact with Zuora Production Copy Environment (PCE).
+//The base URL given through WithBaseURL is turned into a PCEEnvironment.
+//
+//Deprecated: use NewAPI with WithEnvironment(PCEEnvironment(baseURL)).
+func NewPCEAPI(options ...ConfigOption) *API {
+	config := newConfig(options...)
+	config.Environment = PCEEnvironment(config.Environment.BaseURL)
+	return newAPI(newClient(config))
 }
 
-//NewPCEAPI helper function to create all required services to interact with Zuora Production Copy Environment (PCE)
-func NewPCEAPI(httpClient Doer, authHeaderProvider AuthHeaderProvider, baseURL string) *API {
+func newAPI(client *client) *API {
 	return &API{
 		V1: V1{
-			AccountsService:      newAccountsService(httpClient, authHeaderProvider, baseURL),
-			CatalogService:       newCatalogService(httpClient, authHeaderProvider, baseURL),
-			SubscriptionsService: newSubscriptionsService(httpClient, authHeaderProvider, baseURL),
-			DescribeService:      newDescribeService(httpClient, authHeaderProvider, baseURL),
-			ActionsService:       newActionsService(httpClient, authHeaderProvider, baseURL, true),
-			PaymentMethods:       newPaymentMethods(httpClient, authHeaderProvider, baseURL, true),
-			Invoices:             newInvoices(httpClient, authHeaderProvider, baseURL, true),
-			RefundService:        newRefundService(httpClient, authHeaderProvider, baseURL, true),
+			AccountsService:      newAccountsService(client),
+			CatalogService:       newCatalogService(client),
+			SubscriptionsService: newSubscriptionsService(client),
+			DescribeService:      newDescribeService(client),
+			ActionsService:       newActionsService(client),
+			PaymentMethods:       newPaymentMethods(client),
+			Invoices:             newInvoices(client),
+			RefundService:        newRefundService(client),
+			ExportsService:       newExportsService(client),
+			BatchQueryService:    newBatchQueryService(client),
+			DataQueryService:     newDataQueryService(client),
 		},
 		ObjectModel: newObjectModel(),
 	}
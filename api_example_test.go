@@ -3,7 +3,9 @@ package zuora_test
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"os"
 
 	"github.com/hyeomans/zuora"
 )
@@ -16,7 +18,11 @@ func ExampleOAuthHeader() {
 
 	oauthHeaderProvider := zuora.NewOAuthHeader(httpClient, &zuora.MemoryTokenStore{}, "clientID", "clientSecret", "https://apisandbox.zuora.com/")
 
-	zuoraAPI := zuora.NewAPI(httpClient, oauthHeaderProvider, "https://apisandbox.zuora.com/")
+	zuoraAPI := zuora.NewAPI(
+		zuora.WithHTTPClient(httpClient),
+		zuora.WithAuthHeaderProvider(oauthHeaderProvider),
+		zuora.WithBaseURL("https://apisandbox.zuora.com/"),
+	)
 	fmt.Println(zuoraAPI.V1.AccountsService.Summary(ctx, "ObjectID"))
 }
 
@@ -27,7 +33,29 @@ func ExampleBasicAuthHeader() {
 
 	basicAuthHeader := zuora.NewBasicAuthHeader("yourClientID", "yourClientSecret")
 
-	zuoraAPI := zuora.NewAPI(httpClient, basicAuthHeader, "https://apisandbox.zuora.com/")
+	zuoraAPI := zuora.NewAPI(
+		zuora.WithHTTPClient(httpClient),
+		zuora.WithAuthHeaderProvider(basicAuthHeader),
+		zuora.WithBaseURL("https://apisandbox.zuora.com/"),
+	)
+
+	fmt.Println(zuoraAPI.V1.AccountsService.Summary(ctx, "ObjectID"))
+}
+
+//ExampleNewAPI how to initialize the Zuora client with every capability configured through options.
+func ExampleNewAPI() {
+	ctx := context.Background()
+
+	zuoraAPI := zuora.NewAPI(
+		zuora.WithBaseURL("https://rest.apisandbox.zuora.com"),
+		zuora.WithOAuth("clientID", "clientSecret"),
+		zuora.WithRetryPolicy(zuora.DefaultRetryPolicy()),
+		zuora.WithLimiter(zuora.NewLimiter(zuora.LimiterConfig{MaxConcurrent: 20})),
+		zuora.WithLogger(log.New(os.Stderr, "", log.LstdFlags)),
+		zuora.WithVersion("211.0"),
+		zuora.WithUserAgent("my-billing-worker/1.0"),
+		zuora.WithHeader("X-Request-Source", "billing"),
+	)
 
 	fmt.Println(zuoraAPI.V1.AccountsService.Summary(ctx, "ObjectID"))
 }
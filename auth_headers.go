@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 )
 
 // AuthHeaderProvider an interface that defines
@@ -18,9 +21,57 @@ type AuthHeaderProvider interface {
 	AuthHeaders(ctx context.Context) (string, error)
 }
 
+// AuthProvider authenticates requests going out to Zuora by setting
+// one or several headers, as some authentication schemes require.
+type AuthProvider interface {
+	SetAuthHeaders(ctx context.Context, header http.Header) error
+}
+
+// AdaptAuthHeaderProvider turns an AuthHeaderProvider into an AuthProvider
+// setting the Authorization header.
+func AdaptAuthHeaderProvider(authHeaderProvider AuthHeaderProvider) AuthProvider {
+	if authProvider, ok := authHeaderProvider.(AuthProvider); ok {
+		return authProvider
+	}
+
+	return authHeaderAdapter{authHeaderProvider: authHeaderProvider}
+}
+
+type authHeaderAdapter struct {
+	authHeaderProvider AuthHeaderProvider
+}
+
+func (a authHeaderAdapter) SetAuthHeaders(ctx context.Context, header http.Header) error {
+	return setAuthorization(ctx, a.authHeaderProvider, header)
+}
+
+func (a authHeaderAdapter) Invalidate(ctx context.Context, authHeader string) {
+	if invalidator, ok := a.authHeaderProvider.(invalidator); ok {
+		invalidator.Invalidate(ctx, authHeader)
+	}
+}
+
+// invalidator is implemented by auth providers caching credentials, such as OAuthHeader,
+// so a token rejected by Zuora can be dropped.
+type invalidator interface {
+	Invalidate(ctx context.Context, authHeader string)
+}
+
+func setAuthorization(ctx context.Context, authHeaderProvider AuthHeaderProvider, header http.Header) error {
+	authHeader, err := authHeaderProvider.AuthHeaders(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	header.Set("Authorization", authHeader)
+	return nil
+}
+
 //TokenStorer handles token renewal with two simple methods.
 //Token() returns a boolean to indicate a token is valid and if valid, it will return the active token.
 //Update() causes a side-effect to update a token in whichever backing store you choose.
+//TokenStore is the context aware version able to report failures, see AdaptTokenStorer.
 type TokenStorer interface {
 	Token() (bool, *Token)
 	Update(*Token)
@@ -33,110 +84,380 @@ type Token struct {
 	ExpiresIn   int    `json:"expires_in"`
 	Scope       string `json:"scope"`
 	Jti         string `json:"jti"`
+	// Expiry is when the token expires, computed from ExpiresIn when the token is created.
+	Expiry time.Time `json:"expiry,omitempty"`
 }
 
 // BasicAuthHeader represents a basic HTTP auth header
 // holder.
 type BasicAuthHeader struct {
-	clientID     string
-	clientSecret string
+	credentials CredentialSource
 }
 
 // NewBasicAuthHeader initialize with clientID & clientSecret from Zuora.
 func NewBasicAuthHeader(clientID, clientSecret string) *BasicAuthHeader {
-	return &BasicAuthHeader{clientID: clientID, clientSecret: clientSecret}
+	return NewBasicAuthHeaderFromSource(StaticCredentials(clientID, clientSecret))
+}
+
+// NewBasicAuthHeaderFromSource initialize with the username & password provided by credentials.
+func NewBasicAuthHeaderFromSource(credentials CredentialSource) *BasicAuthHeader {
+	return &BasicAuthHeader{credentials: credentials}
 }
 
 // AuthHeaders returns a string that will be added to each request going out
 // to Zuora
 func (t *BasicAuthHeader) AuthHeaders(ctx context.Context) (string, error) {
-	toEncode := fmt.Sprintf("%v:%v", t.clientID, t.clientSecret)
+	credentials, err := t.credentials.Credentials(ctx)
+
+	if err != nil {
+		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to get credentials: %v", err), err: err}
+	}
+
+	toEncode := fmt.Sprintf("%v:%v", credentials.ClientID, credentials.ClientSecret)
 	return fmt.Sprintf("Basic %v", b64.StdEncoding.EncodeToString([]byte(toEncode))), nil
 }
 
-// OAuthHeader is the holder of information to retrieve an OAuth token from Zuora
+// SetAuthHeaders sets the Authorization header.
+func (t *BasicAuthHeader) SetAuthHeaders(ctx context.Context, header http.Header) error {
+	return setAuthorization(ctx, t, header)
+}
+
+// DefaultTokenExpirySkew is how long before its expiry an OAuth token is refreshed.
+const DefaultTokenExpirySkew = time.Minute
+
+// DefaultTokenTimeout is how long fetching an OAuth token may take.
+const DefaultTokenTimeout = 30 * time.Second
+
+// OAuthOption customizes an OAuthHeader.
+type OAuthOption func(*OAuthHeader)
+
+// OAuthExpirySkew refreshes tokens skew before they expire, so requests never start
+// with a token about to expire. Defaults to DefaultTokenExpirySkew.
+func OAuthExpirySkew(skew time.Duration) OAuthOption {
+	return func(t *OAuthHeader) {
+		t.skew = skew
+	}
+}
+
+// OAuthTokenTimeout bounds the token requests, which are shared by concurrent callers and so
+// do not stop when the context of one of them is done. Defaults to DefaultTokenTimeout.
+func OAuthTokenTimeout(timeout time.Duration) OAuthOption {
+	return func(t *OAuthHeader) {
+		t.timeout = timeout
+	}
+}
+
+// OAuthCredentials fetches tokens with the client ID & secret provided by credentials instead of
+// the ones given to NewOAuthHeader. The token is dropped as soon as the credentials change.
+func OAuthCredentials(credentials CredentialSource) OAuthOption {
+	return func(t *OAuthHeader) {
+		t.credentials = credentials
+	}
+}
+
+// OAuthTokenStore keeps tokens in store, for example a FileTokenStore shared by several processes.
+func OAuthTokenStore(store TokenStore) OAuthOption {
+	return func(t *OAuthHeader) {
+		t.store = store
+	}
+}
+
+// OAuthHeader is the holder of information to retrieve an OAuth token from Zuora.
+// Concurrent callers needing a new token share a single request to Zuora.
 type OAuthHeader struct {
-	clientID     string
-	clientSecret string
-	http         Doer
-	baseURL      string
-	tokenStorer  TokenStorer
+	credentials CredentialSource
+	http        Doer
+	baseURL     string
+	store       TokenStore
+	skew        time.Duration
+	timeout     time.Duration
+
+	mu       sync.Mutex
+	inflight *tokenCall
+	rejected string
+	// fetched is the last token fetched by this OAuthHeader and fetchedWith the credentials used.
+	fetched     string
+	fetchedWith Credentials
+}
+
+// tokenCall is a token request shared by every caller waiting for it.
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
 }
 
 // NewOAuthHeader initialize OAuthHeader struct with information coming from Zuora.
-func NewOAuthHeader(doer Doer, tokenStorer TokenStorer, clientID, clientSecret, baseURL string) *OAuthHeader {
-	return &OAuthHeader{
-		http:         doer,
-		clientID:     clientID,
-		clientSecret: clientSecret,
-		baseURL:      baseURL,
-		tokenStorer:  tokenStorer,
+// Pass OAuthTokenStore to keep tokens in a TokenStore instead of tokenStorer, which can then be nil.
+func NewOAuthHeader(doer Doer, tokenStorer TokenStorer, clientID, clientSecret, baseURL string, options ...OAuthOption) *OAuthHeader {
+	t := &OAuthHeader{
+		http:        doer,
+		credentials: StaticCredentials(clientID, clientSecret),
+		baseURL:     baseURL,
+		store:       AdaptTokenStorer(tokenStorer),
+		skew:        DefaultTokenExpirySkew,
+		timeout:     DefaultTokenTimeout,
+	}
+
+	for _, option := range options {
+		option(t)
+	}
+
+	if t.store == nil {
+		t.store = AdaptTokenStorer(&MemoryTokenStore{})
 	}
+
+	return t
 }
 
 // AuthHeaders returns a string that will be added to each request going out
 // to Zuora.
 func (t *OAuthHeader) AuthHeaders(ctx context.Context) (string, error) {
-	isValid, token := t.tokenStorer.Token()
+	token, err := t.token(ctx)
 
-	if isValid {
-		return fmt.Sprintf("Bearer %v", token.AccessToken), nil
+	if err != nil {
+		return "", err
 	}
 
+	return fmt.Sprintf("Bearer %v", token.AccessToken), nil
+}
+
+// SetAuthHeaders sets the Authorization header.
+func (t *OAuthHeader) SetAuthHeaders(ctx context.Context, header http.Header) error {
+	return setAuthorization(ctx, t, header)
+}
+
+// Invalidate drops the token sent in authHeader after Zuora rejected it, the next call
+// to AuthHeaders fetches a new one.
+func (t *OAuthHeader) Invalidate(ctx context.Context, authHeader string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rejected = strings.TrimPrefix(authHeader, "Bearer ")
+}
+
+// token returns the stored token when it is still fresh, otherwise it joins the
+// request in flight or starts a new one.
+func (t *OAuthHeader) token(ctx context.Context) (*Token, error) {
+	token, ok, err := t.stored(ctx)
+
+	if err != nil || ok {
+		return token, err
+	}
+
+	t.mu.Lock()
+	call := t.inflight
+	leader := call == nil
+
+	if leader {
+		call = &tokenCall{done: make(chan struct{})}
+		t.inflight = call
+	}
+
+	t.mu.Unlock()
+
+	if leader {
+		go t.share(call)
+	}
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// share runs the refresh of call. It does not use the context of any caller, so a caller
+// giving up does not fail the refresh for the others still waiting.
+func (t *OAuthHeader) share(call *tokenCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	call.token, call.err = t.refresh(ctx)
+
+	t.mu.Lock()
+	t.inflight = nil
+	t.mu.Unlock()
+
+	close(call.done)
+}
+
+// refresh fetches a new token and saves it. When the store can be locked, the lock is held
+// meanwhile so other processes sharing the store wait for this token instead of fetching their own.
+func (t *OAuthHeader) refresh(ctx context.Context) (*Token, error) {
+	if locker, ok := t.store.(TokenStoreLocker); ok {
+		unlock, err := locker.Lock(ctx)
+
+		if err != nil {
+			return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to lock the token store: %v", err), err: err}
+		}
+
+		defer unlock()
+	}
+
+	// The token may have been refreshed while waiting for the lock or the previous refresh.
+	if token, ok, err := t.stored(ctx); err != nil || ok {
+		return token, err
+	}
+
+	credentials, err := t.credentials.Credentials(ctx)
+
+	if err != nil {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to get credentials: %v", err), err: err}
+	}
+
+	token, err := t.fetch(ctx, credentials)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.store.Update(ctx, token); err != nil {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to update the token store: %v", err), err: err}
+	}
+
+	t.mu.Lock()
+	t.fetched, t.fetchedWith = token.AccessToken, credentials
+	t.mu.Unlock()
+
+	return token, nil
+}
+
+// stored returns the token in the store unless it expires within the skew, was rejected by Zuora
+// or was fetched by this OAuthHeader with credentials that have changed since.
+func (t *OAuthHeader) stored(ctx context.Context) (*Token, bool, error) {
+	token, err := t.store.Token(ctx)
+
+	if err != nil {
+		return nil, false, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to read the token store: %v", err), err: err}
+	}
+
+	credentials, err := t.credentials.Credentials(ctx)
+
+	if err != nil {
+		return nil, false, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to get credentials: %v", err), err: err}
+	}
+
+	t.mu.Lock()
+	rejected := t.rejected
+	rotated := token != nil && token.AccessToken == t.fetched && credentials != t.fetchedWith
+	t.mu.Unlock()
+
+	if token == nil || token.AccessToken == rejected || rotated {
+		return nil, false, nil
+	}
+
+	if token.Expiry.IsZero() {
+		return token, true, nil
+	}
+
+	skew := t.skew
+	if lifetime := time.Duration(token.ExpiresIn) * time.Second; skew >= lifetime {
+		skew = lifetime / 2
+	}
+
+	return token, time.Now().Add(skew).Before(token.Expiry), nil
+}
+
+// fetch requests a new token from Zuora.
+func (t *OAuthHeader) fetch(ctx context.Context, credentials Credentials) (*Token, error) {
 	tokenURL := fmt.Sprint(t.baseURL, "/oauth/token")
 	data := url.Values{}
 
 	data.Add("grant_type", "client_credentials")
-	data.Add("client_id", t.clientID)
-	data.Add("client_secret", t.clientSecret)
+	data.Add("client_id", credentials.ClientID)
+	data.Add("client_secret", credentials.ClientSecret)
 
 	values := strings.NewReader(data.Encode())
 
 	req, err := http.NewRequest(http.MethodPost, tokenURL, values)
 
 	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
 	}
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
+	requestedAt := time.Now()
 	res, err := t.http.Do(req.WithContext(ctx))
 
 	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
+		netErr, ok := err.(net.Error)
+		isTemporary := ok && netErr.Timeout() && ctx.Err() == nil
+		return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to make request: %v", err), err: err}
 	}
 
 	defer res.Body.Close()
 
-	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
 	body, err := ioutil.ReadAll(res.Body)
 
 	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to read body response into memory: %v", err)}
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to read body response into memory: %v", err)}
 	}
 
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		return "", responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory: %v - data: %v", err, string(body))}
+		zuoraErr := newError(req, res.StatusCode, body, fmt.Sprintf("token request was rejected with status %v - data: %v", res.StatusCode, string(body)))
+		zuoraErr.retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+		return nil, zuoraErr
 	}
 
 	jsonResponse := Token{}
 
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
+	}
+
+	jsonResponse.Expiry = requestedAt.Add(time.Duration(jsonResponse.ExpiresIn) * time.Second)
+	return &jsonResponse, nil
+}
+
+// APIKeyHeader authenticates with the apiAccessKeyId and apiSecretAccessKey headers.
+// More info at: https://www.zuora.com/developer/api-reference/#section/Authentication/Other-Supported-Authentication-Schemes
+type APIKeyHeader struct {
+	credentials CredentialSource
+}
+
+// NewAPIKeyHeader initialize with the API access key ID & secret access key of a Zuora user.
+func NewAPIKeyHeader(accessKeyID, secretAccessKey string) *APIKeyHeader {
+	return NewAPIKeyHeaderFromSource(StaticCredentials(accessKeyID, secretAccessKey))
+}
+
+// NewAPIKeyHeaderFromSource initialize with the access key ID & secret access key provided by credentials.
+func NewAPIKeyHeaderFromSource(credentials CredentialSource) *APIKeyHeader {
+	return &APIKeyHeader{credentials: credentials}
+}
+
+// SetAuthHeaders sets the apiAccessKeyId and apiSecretAccessKey headers.
+func (t *APIKeyHeader) SetAuthHeaders(ctx context.Context, header http.Header) error {
+	credentials, err := t.credentials.Credentials(ctx)
+
+	if err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while trying to get credentials: %v", err), err: err}
 	}
 
-	t.tokenStorer.Update(&jsonResponse)
+	header.Set("apiAccessKeyId", credentials.ClientID)
+	header.Set("apiSecretAccessKey", credentials.ClientSecret)
+	return nil
+}
+
+// MultiOrgOAuthHeader authenticates with an OAuth token and sends the Zuora-Org-Ids header,
+// required to access the organizations of a multi-org tenant.
+type MultiOrgOAuthHeader struct {
+	*OAuthHeader
+	orgIDs []string
+}
+
+// NewMultiOrgOAuthHeader sends orgIDs along with the tokens provided by oauthHeader.
+func NewMultiOrgOAuthHeader(oauthHeader *OAuthHeader, orgIDs ...string) *MultiOrgOAuthHeader {
+	return &MultiOrgOAuthHeader{OAuthHeader: oauthHeader, orgIDs: orgIDs}
+}
+
+// SetAuthHeaders sets the Authorization and Zuora-Org-Ids headers.
+func (t *MultiOrgOAuthHeader) SetAuthHeaders(ctx context.Context, header http.Header) error {
+	if err := t.OAuthHeader.SetAuthHeaders(ctx, header); err != nil {
+		return err
+	}
 
-	return fmt.Sprintf("Bearer %v", jsonResponse.AccessToken), nil
+	header.Set("Zuora-Org-Ids", strings.Join(t.orgIDs, ","))
+	return nil
 }
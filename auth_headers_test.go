@@ -2,9 +2,13 @@ package zuora
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestAuthHeaders(t *testing.T) {
@@ -86,7 +90,7 @@ func TestOAuthHeadersWhenTokenFails(t *testing.T) {
 	defer mockServer.Close()
 
 	oauthHeader := NewOAuthHeader(mockServer.Client(), &MemoryTokenStore{}, "testClientID", "testClientSecret", mockServer.URL)
-	want := `error while trying to read body response into memory: <nil> - data: {"something": "happened"}`
+	want := `token request was rejected with status 401 - data: {"something": "happened"}`
 	g, err := oauthHeader.AuthHeaders(ctx)
 
 	t.Log(g, err)
@@ -94,3 +98,179 @@ func TestOAuthHeadersWhenTokenFails(t *testing.T) {
 		t.Errorf("OAuthHeader.AuthHeaders() wanted an error: %v but got: %v", want, err)
 	}
 }
+
+func TestOAuthHeadersSingleFlight(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"access_token": "reallylongaccesstoken", "token_type": "fake", "expires_in": 3600, "scope": "all", "jti": ""}`))
+	}))
+	defer mockServer.Close()
+
+	oauthHeader := NewOAuthHeader(mockServer.Client(), &MemoryTokenStore{}, "testClientID", "testClientSecret", mockServer.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := oauthHeader.AuthHeaders(ctx); err != nil {
+				t.Errorf("OAuthHeader.AuthHeaders() returned an error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("OAuthHeader.AuthHeaders() requested %v tokens, want 1", calls)
+	}
+}
+
+func TestOAuthHeadersSharedFetchOutlivesCaller(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"access_token": "reallylongaccesstoken", "token_type": "fake", "expires_in": 3600, "scope": "all", "jti": ""}`))
+	}))
+	defer mockServer.Close()
+
+	oauthHeader := NewOAuthHeader(mockServer.Client(), &MemoryTokenStore{}, "testClientID", "testClientSecret", mockServer.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	impatient := make(chan error, 1)
+	go func() {
+		_, err := oauthHeader.AuthHeaders(ctx)
+		impatient <- err
+	}()
+
+	time.Sleep(5 * time.Millisecond) //Joins the request started by the impatient caller
+
+	if _, err := oauthHeader.AuthHeaders(context.Background()); err != nil {
+		t.Errorf("OAuthHeader.AuthHeaders() returned an error: %v", err)
+	}
+
+	if err := <-impatient; err != context.DeadlineExceeded {
+		t.Errorf("OAuthHeader.AuthHeaders() = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if calls != 1 {
+		t.Errorf("OAuthHeader.AuthHeaders() requested %v tokens, want 1", calls)
+	}
+}
+
+func TestOAuthHeadersRefreshBeforeExpiry(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"access_token": "reallylongaccesstoken", "token_type": "fake", "expires_in": 1, "scope": "all", "jti": ""}`))
+	}))
+	defer mockServer.Close()
+
+	oauthHeader := NewOAuthHeader(mockServer.Client(), &MemoryTokenStore{}, "testClientID", "testClientSecret", mockServer.URL, OAuthExpirySkew(400*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			time.Sleep(700 * time.Millisecond) //The token is still valid for 300ms but within the skew
+		}
+
+		if _, err := oauthHeader.AuthHeaders(ctx); err != nil {
+			t.Errorf("OAuthHeader.AuthHeaders() returned an error: %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("OAuthHeader.AuthHeaders() requested %v tokens, want 2", calls)
+	}
+}
+
+func TestOAuthHeadersRetryOnUnauthorized(t *testing.T) {
+	ctx := context.Background()
+	var tokens, requests int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/oauth/token" {
+			n := atomic.AddInt32(&tokens, 1)
+			rw.WriteHeader(200)
+			fmt.Fprintf(rw, `{"access_token": "token%v", "token_type": "fake", "expires_in": 3600, "scope": "all", "jti": ""}`, n)
+			return
+		}
+
+		atomic.AddInt32(&requests, 1)
+		if req.Header.Get("Authorization") != "Bearer token2" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			rw.Write([]byte(`{"message": "Authentication error"}`))
+			return
+		}
+
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewAPI(WithHTTPClient(mockServer.Client()), WithBaseURL(mockServer.URL), WithOAuth("testClientID", "testClientSecret"))
+
+	if _, err := api.V1.AccountsService.Get(ctx, "A0001"); err != nil {
+		t.Errorf("AccountsService.Get() returned an error: %v", err)
+	}
+
+	if tokens != 2 || requests != 2 {
+		t.Errorf("AccountsService.Get() requested %v tokens and sent %v requests, want 2 and 2", tokens, requests)
+	}
+}
+
+func TestAuthProviders(t *testing.T) {
+	ctx := context.Background()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/oauth/token" {
+			rw.WriteHeader(200)
+			rw.Write([]byte(`{"access_token": "reallylongaccesstoken", "token_type": "fake", "expires_in": 3600, "scope": "all", "jti": ""}`))
+			return
+		}
+
+		want := map[string]string{
+			"Authorization":      "Bearer reallylongaccesstoken",
+			"Zuora-Org-Ids":      "org1,org2",
+			"apiAccessKeyId":     "",
+			"apiSecretAccessKey": "",
+		}
+
+		if req.URL.Path == "/v1/accounts/apikey" {
+			want = map[string]string{
+				"Authorization":      "",
+				"Zuora-Org-Ids":      "",
+				"apiAccessKeyId":     "testAccessKeyID",
+				"apiSecretAccessKey": "testSecretAccessKey",
+			}
+		}
+
+		for header, want := range want {
+			if got := req.Header.Get(header); got != want {
+				t.Errorf("header %v = %q, want %q", header, got, want)
+			}
+		}
+
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewAPI(WithHTTPClient(mockServer.Client()), WithBaseURL(mockServer.URL), WithAPIKey("testAccessKeyID", "testSecretAccessKey"))
+
+	if _, err := api.V1.AccountsService.Get(ctx, "apikey"); err != nil {
+		t.Errorf("AccountsService.Get() returned an error: %v", err)
+	}
+
+	api = NewAPI(WithHTTPClient(mockServer.Client()), WithBaseURL(mockServer.URL), WithMultiOrgOAuth("testClientID", "testClientSecret", []string{"org1", "org2"}))
+
+	if _, err := api.V1.AccountsService.Get(ctx, "multiorg"); err != nil {
+		t.Errorf("AccountsService.Get() returned an error: %v", err)
+	}
+}
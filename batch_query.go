@@ -0,0 +1,129 @@
+package zuora
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type batchQueryService struct {
+	client *client
+}
+
+func newBatchQueryService(client *client) *batchQueryService {
+	return &batchQueryService{
+		client: client,
+	}
+}
+
+// Submit starts an AQuA job running every query of jobRequest.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/POST_BatchQueryJob
+func (t *batchQueryService) Submit(ctx context.Context, jobRequest BatchQueryJobRequest) (BatchQueryJob, error) {
+	if (jobRequest.Partner == "") != (jobRequest.Project == "") {
+		return BatchQueryJob{}, responseError{isTemporary: false, message: "a stateful AQuA job needs both a partner and a project"}
+	}
+
+	payload := batchQueryJobPayload{
+		Format:          jobRequest.Format,
+		Version:         "1.1",
+		Name:            jobRequest.Name,
+		Encrypted:       "none",
+		Compression:     jobRequest.Compression,
+		Partner:         jobRequest.Partner,
+		Project:         jobRequest.Project,
+		IncrementalTime: jobRequest.IncrementalTime,
+	}
+
+	if payload.Format == "" {
+		payload.Format = BatchQueryFormatCSV
+	}
+
+	for _, batchQuery := range jobRequest.Queries {
+		query, err := buildQuery(batchQuery.Query)
+
+		if err != nil {
+			return BatchQueryJob{}, err
+		}
+
+		queryType := batchQuery.Type
+		if queryType == "" {
+			queryType = BatchQueryTypeZOQLExport
+		}
+
+		payload.Queries = append(payload.Queries, batchQueryPayload{Name: batchQuery.Name, Query: query, Type: queryType})
+	}
+
+	return t.job(ctx, request{method: http.MethodPost, path: "/v1/batch-query/", body: payload})
+}
+
+// Get returns the job jobID and the state of its queries.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/GET_BatchQueryJob
+func (t *batchQueryService) Get(ctx context.Context, jobID string) (BatchQueryJob, error) {
+	path := fmt.Sprintf("/v1/batch-query/jobs/%v", url.PathEscape(jobID))
+	return t.job(ctx, request{method: http.MethodGet, path: path})
+}
+
+// LastJob returns the last job of a stateful partner and project.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/GET_LastBatchQueryJob
+func (t *batchQueryService) LastJob(ctx context.Context, partner, project string) (BatchQueryJob, error) {
+	path := fmt.Sprintf("/v1/batch-query/jobs/partner/%v/project/%v", url.PathEscape(partner), url.PathEscape(project))
+	return t.job(ctx, request{method: http.MethodGet, path: path})
+}
+
+// Cancel cancels the job jobID.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/DELETE_BatchQueryJob
+func (t *batchQueryService) Cancel(ctx context.Context, jobID string) (BatchQueryJob, error) {
+	path := fmt.Sprintf("/v1/batch-query/jobs/%v", url.PathEscape(jobID))
+	return t.job(ctx, request{method: http.MethodDelete, path: path})
+}
+
+// Wait polls the job jobID, waiting longer between every poll as set by WithPollInterval,
+// until it is completed. A job ending with an error, aborted or cancelled returns an error along with the job.
+func (t *batchQueryService) Wait(ctx context.Context, jobID string) (BatchQueryJob, error) {
+	var job BatchQueryJob
+
+	err := t.client.poll(ctx, func() (bool, error) {
+		var err error
+
+		if job, err = t.Get(ctx, jobID); err != nil {
+			return false, err
+		}
+
+		switch job.Status {
+		case BatchQueryStatusCompleted:
+			return true, nil
+		case BatchQueryStatusError, BatchQueryStatusAborted, BatchQueryStatusCancelled:
+			return false, &JobError{Job: "AQuA job", ID: jobID, Status: string(job.Status), Message: job.Message}
+		}
+
+		return false, nil
+	})
+
+	return job, err
+}
+
+// Download streams the result file fileID of a query, see BatchQueryResult.FileID.
+// Files are compressed when the job asked so. The caller must close the returned reader.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/GET_File
+func (t *batchQueryService) Download(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/v1/file/%v", url.PathEscape(fileID))
+	return t.client.stream(ctx, request{method: http.MethodGet, path: path})
+}
+
+// job sends r and decodes the AQuA job it returns. AQuA reports failures through
+// errorCode and message rather than a success flag.
+func (t *batchQueryService) job(ctx context.Context, r request) (BatchQueryJob, error) {
+	jsonResponse := BatchQueryJob{}
+
+	if err := t.client.doJSON(ctx, r, &jsonResponse); err != nil {
+		return BatchQueryJob{}, err
+	}
+
+	if jsonResponse.ErrorCode != "" {
+		return jsonResponse, &JobError{Job: "AQuA job", ID: jsonResponse.ID, Status: string(jsonResponse.Status), Code: jsonResponse.ErrorCode, Message: jsonResponse.Message}
+	}
+
+	return jsonResponse, nil
+}
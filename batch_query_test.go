@@ -0,0 +1,105 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBatchQueryService(t *testing.T) {
+	polls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if got := req.Header.Get("Zuora-Entity-Ids"); got != "entity" {
+			t.Errorf("header Zuora-Entity-Ids = %q, want %q", got, "entity")
+		}
+
+		switch req.URL.Path {
+		case "/v1/batch-query/":
+			payload := batchQueryJobPayload{}
+			json.NewDecoder(req.Body).Decode(&payload)
+
+			if payload.Format != BatchQueryFormatCSV || payload.Partner != "warehouse" || payload.Project != "nightly" || payload.Compression != BatchQueryCompressionGZIP ||
+				len(payload.Queries) != 1 || payload.Queries[0].Query != "select Id from Account" || payload.Queries[0].Type != BatchQueryTypeZOQLExport {
+				t.Errorf("AQuA payload = %+v", payload)
+			}
+
+			rw.Write([]byte(`{"id": "job1", "status": "submitted", "batches": [{"name": "accounts", "status": "pending"}]}`))
+		case "/v1/batch-query/jobs/job1":
+			polls++
+
+			if polls < 2 {
+				rw.Write([]byte(`{"id": "job1", "status": "executing", "batches": [{"name": "accounts", "status": "executing"}]}`))
+				return
+			}
+
+			rw.Write([]byte(`{"id": "job1", "status": "completed", "batches": [{"name": "accounts", "status": "completed", "fileId": "file1", "recordCount": 1}]}`))
+		case "/v1/batch-query/jobs/partner/warehouse/project/nightly":
+			rw.Write([]byte(`{"id": "job0", "status": "completed"}`))
+		case "/v1/file/file1":
+			rw.Write([]byte("Account.Id\n1\n"))
+		default:
+			t.Errorf("unexpected request to %v", req.URL)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	ctx := WithCallOptions(context.Background(), CallEntityIds("entity"))
+	api := newTestAPI(mockServer, WithPollInterval(time.Millisecond, 5*time.Millisecond))
+
+	last, err := api.V1.BatchQueryService.LastJob(ctx, "warehouse", "nightly")
+	if err != nil || last.ID != "job0" {
+		t.Errorf("BatchQueryService.LastJob() = %+v, %v", last, err)
+	}
+
+	job, err := api.V1.BatchQueryService.Submit(ctx, BatchQueryJobRequest{
+		Name:        "nightly",
+		Partner:     "warehouse",
+		Project:     "nightly",
+		Compression: BatchQueryCompressionGZIP,
+		Queries:     []BatchQuery{{Name: "accounts", Query: Select("Id").From("Account")}},
+	})
+	if err != nil {
+		t.Fatalf("BatchQueryService.Submit() returned an error: %v", err)
+	}
+
+	job, err = api.V1.BatchQueryService.Wait(ctx, job.ID)
+	if err != nil || len(job.Batches) != 1 || job.Batches[0].FileID != "file1" {
+		t.Fatalf("BatchQueryService.Wait() = %+v, %v", job, err)
+	}
+
+	file, err := api.V1.BatchQueryService.Download(ctx, job.Batches[0].FileID)
+	if err != nil {
+		t.Fatalf("BatchQueryService.Download() returned an error: %v", err)
+	}
+	defer file.Close()
+
+	if data, _ := ioutil.ReadAll(file); string(data) != "Account.Id\n1\n" {
+		t.Errorf("BatchQueryService.Download() = %q", data)
+	}
+}
+
+func TestBatchQueryServiceErrorCode(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"errorCode": "90011", "message": "invalid query"}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+
+	_, err := api.V1.BatchQueryService.Get(context.Background(), "job1")
+	jobErr := &JobError{}
+
+	if !errors.As(err, &jobErr) || jobErr.Code != "90011" || jobErr.Message != "invalid query" {
+		t.Errorf("BatchQueryService.Get() = %v, want a *JobError with the code and message", err)
+	}
+
+	if _, err := api.V1.BatchQueryService.Submit(context.Background(), BatchQueryJobRequest{Partner: "warehouse"}); err == nil {
+		t.Error("BatchQueryService.Submit() wanted an error for a partner without project but got nil")
+	}
+}
@@ -0,0 +1,121 @@
+package zuora
+
+//BatchQueryFormat is the format of the files produced by an AQuA job.
+type BatchQueryFormat string
+
+//AQuA output formats.
+const (
+	BatchQueryFormatCSV  BatchQueryFormat = "csv"
+	BatchQueryFormatJSON BatchQueryFormat = "json"
+)
+
+//BatchQueryCompression is the compression of the files produced by an AQuA job.
+type BatchQueryCompression string
+
+//AQuA compressions, files are not compressed by default.
+const (
+	BatchQueryCompressionNone BatchQueryCompression = "NONE"
+	BatchQueryCompressionZIP  BatchQueryCompression = "ZIP"
+	BatchQueryCompressionGZIP BatchQueryCompression = "GZIP"
+)
+
+//BatchQueryType is the language of a query in an AQuA job.
+type BatchQueryType string
+
+//AQuA query types.
+const (
+	BatchQueryTypeZOQL       BatchQueryType = "zoql"
+	BatchQueryTypeZOQLExport BatchQueryType = "zoqlexport"
+)
+
+//BatchQueryStatus is the status of an AQuA job or of one of its queries.
+type BatchQueryStatus string
+
+//AQuA statuses, a job is over once it is completed, error, aborted or cancelled.
+const (
+	BatchQueryStatusSubmitted BatchQueryStatus = "submitted"
+	BatchQueryStatusPending   BatchQueryStatus = "pending"
+	BatchQueryStatusExecuting BatchQueryStatus = "executing"
+	BatchQueryStatusCompleted BatchQueryStatus = "completed"
+	BatchQueryStatusError     BatchQueryStatus = "error"
+	BatchQueryStatusAborted   BatchQueryStatus = "aborted"
+	BatchQueryStatusCancelled BatchQueryStatus = "cancelled"
+)
+
+//BatchQueryJobRequest describes an AQuA job. Setting Partner and Project makes the job stateful:
+//every query only returns the records changed since the previous job of the same partner and project.
+//More info at: https://knowledgecenter.zuora.com/Central_Platform/API/AB_Aggregate_Query_API
+type BatchQueryJobRequest struct {
+	Name string
+	// Format of the files, defaults to BatchQueryFormatCSV.
+	Format BatchQueryFormat
+	// Compression of the files, defaults to BatchQueryCompressionNone.
+	Compression BatchQueryCompression
+	// Partner and Project identify a stateful job, both must be set together.
+	Partner string
+	Project string
+	// IncrementalTime overrides the time records must have changed since in a stateful job,
+	// formatted as 2006-01-02 15:04:05.
+	IncrementalTime string
+	// Required. Queries run by the job, each one produces a file.
+	Queries []BatchQuery
+}
+
+//BatchQuery is a query of an AQuA job.
+type BatchQuery struct {
+	// Required. Name of the query, unique in the job.
+	Name string
+	// Required. ZOQL or Export ZOQL query.
+	Query Querier
+	// Type of the query, defaults to BatchQueryTypeZOQLExport.
+	Type BatchQueryType
+}
+
+type batchQueryJobPayload struct {
+	Format          BatchQueryFormat      `json:"format"`
+	Version         string                `json:"version"`
+	Name            string                `json:"name,omitempty"`
+	Encrypted       string                `json:"encrypted"`
+	Compression     BatchQueryCompression `json:"compression,omitempty"`
+	Partner         string                `json:"partner,omitempty"`
+	Project         string                `json:"project,omitempty"`
+	IncrementalTime string                `json:"incrementalTime,omitempty"`
+	Queries         []batchQueryPayload   `json:"queries"`
+}
+
+type batchQueryPayload struct {
+	Name  string         `json:"name"`
+	Query string         `json:"query"`
+	Type  BatchQueryType `json:"type"`
+}
+
+//BatchQueryJob is an AQuA job and the state of its queries.
+type BatchQueryJob struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Status          BatchQueryStatus   `json:"status"`
+	Format          BatchQueryFormat   `json:"format"`
+	Version         string             `json:"version"`
+	Encrypted       string             `json:"encrypted"`
+	Partner         string             `json:"partner"`
+	Project         string             `json:"project"`
+	StartTime       string             `json:"startTime"`
+	IncrementalTime string             `json:"incrementalTime"`
+	ErrorCode       string             `json:"errorCode"`
+	Message         string             `json:"message"`
+	Batches         []BatchQueryResult `json:"batches"`
+}
+
+//BatchQueryResult is the state of a query of an AQuA job. FileID is set once it completed.
+type BatchQueryResult struct {
+	BatchID     string           `json:"batchId"`
+	Name        string           `json:"name"`
+	Query       string           `json:"query"`
+	BatchType   BatchQueryType   `json:"batchType"`
+	Status      BatchQueryStatus `json:"status"`
+	FileID      string           `json:"fileId"`
+	RecordCount int              `json:"recordCount"`
+	Full        bool             `json:"full"`
+	Message     string           `json:"message"`
+	ErrorCode   string           `json:"errorCode"`
+}
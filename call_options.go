@@ -0,0 +1,101 @@
+package zuora
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CallOption customizes a single call to Zuora. Attach call options to the context
+// given to any service method with WithCallOptions.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	headers http.Header
+	timeout time.Duration
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx carrying options. Options already attached
+// to ctx are kept, and the new ones are applied on top of them.
+func WithCallOptions(ctx context.Context, options ...CallOption) context.Context {
+	current := callOptionsFrom(ctx)
+	next := callOptions{headers: http.Header{}, timeout: current.timeout}
+
+	for key, values := range current.headers {
+		next.headers[key] = append([]string{}, values...)
+	}
+
+	for _, option := range options {
+		option(&next)
+	}
+
+	return context.WithValue(ctx, callOptionsKey{}, next)
+}
+
+// CallHeader sets a header on the request.
+func CallHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		o.headers.Set(key, value)
+	}
+}
+
+// CallVersion pins the zuora-version of the request, overriding WithVersion.
+func CallVersion(version string) CallOption {
+	return CallHeader("zuora-version", version)
+}
+
+// CallEntityIds sends the request on behalf of the given entities in a multi-entity tenant.
+func CallEntityIds(entityIds ...string) CallOption {
+	return CallHeader("Zuora-Entity-Ids", strings.Join(entityIds, ","))
+}
+
+// CallTrackID sets the Zuora-Track-Id header used to correlate the request in Zuora logs.
+func CallTrackID(trackID string) CallOption {
+	return CallHeader("Zuora-Track-Id", trackID)
+}
+
+// CallIdempotencyKey sets the Idempotency-Key header, which also allows the client to retry
+// non-idempotent requests such as POST.
+func CallIdempotencyKey(key string) CallOption {
+	return CallHeader("Idempotency-Key", key)
+}
+
+// CallTimeout bounds the whole call, retries included.
+func CallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+func callOptionsFrom(ctx context.Context) callOptions {
+	options, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return options
+}
+
+// idempotencyKey returns the key set through CallIdempotencyKey or, failing that, ContextKeyIdempotencyKey.
+func idempotencyKey(ctx context.Context) (string, bool) {
+	if key := callOptionsFrom(ctx).headers.Get("Idempotency-Key"); key != "" {
+		return key, true
+	}
+
+	return contextString(ctx, ContextKeyIdempotencyKey)
+}
+
+// contextString reads key from ctx. Values that are not strings are formatted instead of
+// panicking, so a fmt.Stringer or a number set by mistake still produces a header.
+func contextString(ctx context.Context, key ContextKey) (string, bool) {
+	switch value := ctx.Value(key).(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	case []string:
+		return strings.Join(value, ","), true
+	default:
+		return fmt.Sprint(value), true
+	}
+}
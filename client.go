@@ -0,0 +1,518 @@
+package zuora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+// DoerFunc is an adapter to allow the use of ordinary functions as a Doer.
+type DoerFunc func(request *http.Request) (*http.Response, error)
+
+// Do calls f(request).
+func (f DoerFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// Middleware wraps a Doer to add behaviour around every request that goes out to Zuora.
+// Middlewares run in the order they were registered, the first one being the outermost.
+type Middleware func(next Doer) Doer
+
+// client is the single request executor shared by every service. It takes care of
+// building the request, running the middleware chain, checking the HTTP status and
+// decoding Zuora's responses so all endpoints behave the same way.
+type client struct {
+	httpClient   Doer
+	authProvider AuthProvider
+	environment  Environment
+	middlewares  []Middleware
+	retryPolicy  *RetryPolicy
+	limiter      *Limiter
+	logger       Logger
+	headers      http.Header
+	http         Doer
+
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+
+	actionConcurrency int
+	validator         *Validator
+}
+
+func newClient(config Config) *client {
+	headers := http.Header{}
+
+	for key, values := range config.Headers {
+		headers[key] = append([]string{}, values...)
+	}
+
+	if config.UserAgent != "" {
+		headers.Set("User-Agent", config.UserAgent)
+	}
+
+	if config.Version != "" {
+		headers.Set("zuora-version", config.Version)
+	}
+
+	c := &client{
+		httpClient:   config.HTTPClient,
+		authProvider: config.AuthProvider,
+		environment:  config.Environment,
+		middlewares:  config.Middlewares,
+		retryPolicy:  config.RetryPolicy,
+		limiter:      config.Limiter,
+		logger:       config.Logger,
+		headers:      headers,
+
+		pollInterval:    config.PollInterval,
+		maxPollInterval: config.MaxPollInterval,
+
+		actionConcurrency: config.ActionConcurrency,
+		validator:         config.Validator,
+	}
+
+	if c.pollInterval <= 0 {
+		c.pollInterval = time.Second
+	}
+
+	if c.maxPollInterval <= 0 {
+		c.maxPollInterval = 30 * time.Second
+	}
+
+	if c.maxPollInterval < c.pollInterval {
+		c.maxPollInterval = c.pollInterval
+	}
+
+	c.http = c.chain()
+	return c
+}
+
+// chain builds the Doer every request goes through: logging, limiting, authentication
+// and headers first, then user middlewares, so they see the final request.
+func (c *client) chain() Doer {
+	all := []Middleware{}
+
+	if c.logger != nil {
+		all = append(all, loggerMiddleware(c.logger))
+	}
+
+	if c.limiter != nil {
+		all = append(all, limiterMiddleware(c.limiter))
+	}
+
+	if c.authProvider != nil {
+		all = append(all, authMiddleware(c.authProvider))
+	}
+
+	all = append(all, defaultHeadersMiddleware(c.headers), contextHeadersMiddleware())
+	all = append(all, c.middlewares...)
+	return chain(transport(c.httpClient), all...)
+}
+
+// chain wraps doer with middlewares, the first middleware being the outermost one.
+func chain(doer Doer, middlewares ...Middleware) Doer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		doer = middlewares[i](doer)
+	}
+
+	return doer
+}
+
+// transport is the innermost Doer, it sends the request with the underlying HTTP client.
+func transport(httpClient Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		res, err := httpClient.Do(req)
+
+		if err != nil {
+			netErr, ok := err.(net.Error)
+			isTemporary := ok && netErr.Timeout() && req.Context().Err() == nil
+			return nil, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to make request: %v", err), err: err}
+		}
+
+		return res, nil
+	})
+}
+
+// authMiddleware sets the auth headers. When Zuora answers 401 and the provider
+// caches its credentials, they are invalidated and the request is sent once more.
+func authMiddleware(authProvider AuthProvider) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if err := authProvider.SetAuthHeaders(req.Context(), req.Header); err != nil {
+				return nil, responseError{isTemporary: temporary(err), message: fmt.Sprintf("error while trying to set auth headers: %v", err), err: err}
+			}
+
+			res, err := next.Do(req)
+
+			invalidator, ok := authProvider.(invalidator)
+			if err != nil || res.StatusCode != http.StatusUnauthorized || !ok || (req.Body != nil && req.GetBody == nil) {
+				return res, err
+			}
+
+			res.Body.Close()
+			invalidator.Invalidate(req.Context(), req.Header.Get("Authorization"))
+			retry := req.Clone(req.Context())
+
+			if err := authProvider.SetAuthHeaders(req.Context(), retry.Header); err != nil {
+				return nil, responseError{isTemporary: temporary(err), message: fmt.Sprintf("error while trying to set auth headers: %v", err), err: err}
+			}
+
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to rewind request body: %v", err), err: err}
+				}
+			}
+
+			return next.Do(retry)
+		})
+	}
+}
+
+func loggerMiddleware(logger Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next.Do(req)
+
+			if err != nil {
+				logger.Printf("zuora: %v %v failed after %v: %v", req.Method, req.URL, time.Since(start), err)
+				return nil, err
+			}
+
+			logger.Printf("zuora: %v %v returned %v in %v", req.Method, req.URL, res.StatusCode, time.Since(start))
+			return res, nil
+		})
+	}
+}
+
+func defaultHeadersMiddleware(headers http.Header) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			for key, values := range headers {
+				req.Header[key] = append([]string{}, values...)
+			}
+
+			return next.Do(req)
+		})
+	}
+}
+
+// contextHeadersMiddleware sets the headers carried by the request context, either through
+// the ContextKey values or through WithCallOptions. Call options win when both are set.
+func contextHeadersMiddleware() Middleware {
+	contextHeaders := map[ContextKey]string{
+		ContextKeyZuoraEntityIds: "Zuora-Entity-Ids",
+		ContextKeyZuoraTrackID:   "Zuora-Track-Id",
+		ContextKeyZuoraVersion:   "zuora-version",
+		ContextKeyIdempotencyKey: "Idempotency-Key",
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+
+			for key, header := range contextHeaders {
+				if value, ok := contextString(ctx, key); ok {
+					req.Header.Set(header, value)
+				}
+			}
+
+			for key, values := range callOptionsFrom(ctx).headers {
+				req.Header[key] = append([]string{}, values...)
+			}
+
+			return next.Do(req)
+		})
+	}
+}
+
+// request describes a single call to Zuora.
+type request struct {
+	method string
+	// path is resolved to a full URL through the client environment.
+	path string
+	// body is marshalled to JSON when not nil.
+	body interface{}
+	// idempotent marks POST endpoints that do not modify data, such as queries, as safe to retry.
+	idempotent bool
+	// nonIdempotent marks PUT endpoints with side effects, such as cancelling a subscription,
+	// as unsafe to retry without an idempotency key.
+	nonIdempotent bool
+	// external requests, such as pre-signed file URLs, are sent to path as is with the
+	// underlying HTTP client, without Zuora headers or authentication.
+	external bool
+}
+
+// do sends r through the middleware chain and returns the raw response body.
+// Temporary failures are retried according to the client retry policy, within the
+// CallTimeout attached to ctx if any.
+// When Zuora answers with a logical failure, the body is returned along with the *Error.
+func (c *client) do(ctx context.Context, r request) ([]byte, error) {
+	ctx, cancel := callContext(ctx)
+	defer cancel()
+
+	var body []byte
+	err := c.retry(ctx, r, func(payload []byte) error {
+		var err error
+		body, err = c.send(ctx, r, payload)
+		return err
+	})
+
+	return body, err
+}
+
+// stream works like do but hands the response body over without reading it,
+// for downloads too large to hold in memory. The caller must close it.
+func (c *client) stream(ctx context.Context, r request) (io.ReadCloser, error) {
+	ctx, cancel := callContext(ctx)
+
+	var body io.ReadCloser
+	err := c.retry(ctx, r, func(payload []byte) error {
+		res, err := c.roundTrip(ctx, r, payload)
+
+		if err != nil {
+			return err
+		}
+
+		body = res.Body
+		return nil
+	})
+
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &cancelBody{ReadCloser: body, cancel: cancel}, nil
+}
+
+// callContext applies the CallTimeout attached to ctx.
+func callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout := callOptionsFrom(ctx).timeout; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {}
+}
+
+// cancelBody releases the call context once the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retry marshals the body of r and calls attempt until it succeeds or the retry policy gives up.
+func (c *client) retry(ctx context.Context, r request, attempt func(payload []byte) error) error {
+	var payload []byte
+
+	if r.body != nil {
+		j, err := json.Marshal(r.body)
+
+		if err != nil {
+			return responseError{isTemporary: false, message: fmt.Sprintf("error while trying to convert empty interface: %v", err)}
+		}
+
+		payload = j
+	}
+
+	for n := 1; ; n++ {
+		err := attempt(payload)
+
+		if err == nil {
+			return nil
+		}
+
+		wait, retry := c.retryPolicy.next(ctx, r, n, err)
+
+		if !retry {
+			return err
+		}
+
+		if c.logger != nil {
+			c.logger.Printf("zuora: retrying %v %v in %v after attempt %v failed: %v", r.method, r.path, wait, n, err)
+		}
+
+		if sleep(ctx, wait) != nil {
+			return err
+		}
+	}
+}
+
+// roundTrip makes a single attempt of r and returns the response when its status is a success.
+// Otherwise the body is read into the returned *Error.
+func (c *client) roundTrip(ctx context.Context, r request, payload []byte) (*http.Response, error) {
+	var body io.Reader
+
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	url, doer := c.environment.URL(r.path), c.http
+
+	if r.external {
+		url, doer = r.path, transport(c.httpClient)
+	}
+
+	req, err := http.NewRequest(r.method, url, body)
+
+	if err != nil {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
+	}
+
+	if !r.external {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	res, err := doer.Do(req.WithContext(ctx))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Request == nil {
+		res.Request = req
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	var zuoraErr *Error
+
+	if err != nil {
+		zuoraErr = newError(req, res.StatusCode, nil, fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err))
+	} else {
+		zuoraErr = newError(req, res.StatusCode, resBody, fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(resBody)))
+	}
+
+	zuoraErr.retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+	return nil, zuoraErr
+}
+
+// send makes a single attempt of r and reads the response body.
+func (c *client) send(ctx context.Context, r request, payload []byte) ([]byte, error) {
+	res, err := c.roundTrip(ctx, r, payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to read body response into memory: %v", err), err: err}
+	}
+
+	if err := checkSuccess(res.Request, res.StatusCode, resBody); err != nil {
+		return resBody, err
+	}
+
+	return resBody, nil
+}
+
+// doJSON sends r and unmarshals the JSON response body into out.
+func (c *client) doJSON(ctx context.Context, r request, out interface{}) error {
+	body, err := c.do(ctx, r)
+
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
+	}
+
+	return nil
+}
+
+// poll calls check until it reports the job is done, waiting longer and longer in between
+// according to the client poll interval.
+func (c *client) poll(ctx context.Context, check func() (done bool, err error)) error {
+	wait := c.pollInterval
+
+	for {
+		done, err := check()
+
+		if err != nil || done {
+			return err
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+
+		if wait *= 2; wait > c.maxPollInterval {
+			wait = c.maxPollInterval
+		}
+	}
+}
+
+// checkSuccess returns an *Error when body describes a logical failure, which Zuora
+// sends with a 200 status: a false success flag, a SOAP style fault, or, for action
+// endpoints returning one result per object, any result with a false success flag.
+// Bodies that are not JSON, such as describe XML, are left untouched.
+func checkSuccess(req *http.Request, statusCode int, body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	switch trimmed[0] {
+	case '{':
+		errorResponse := errorResponse{}
+
+		if err := json.Unmarshal(trimmed, &errorResponse); err != nil || !errorResponse.failed() {
+			return nil
+		}
+
+		return newError(req, statusCode, body, "")
+	case '[':
+		results := []errorResponse{}
+
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil
+		}
+
+		zuoraErr := newError(req, statusCode, nil, "")
+		zuoraErr.Body = string(body)
+
+		for i, result := range results {
+			if !result.failed() {
+				continue
+			}
+
+			if len(result.Errors) == 0 {
+				result.Errors = []ObjectError{{Message: "object failed without errors"}}
+			}
+
+			for _, objectError := range result.Errors {
+				objectError.Message = fmt.Sprintf("object %v: %v", i, objectError.Message)
+				zuoraErr.Errors = append(zuoraErr.Errors, objectError)
+			}
+		}
+
+		if len(zuoraErr.Errors) == 0 {
+			return nil
+		}
+
+		return zuoraErr
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hyeomans/zuora"
+)
+
+// errDrift is returned by runDrift when the schemas differ, so main exits with status 2.
+var errDrift = errors.New("schemas differ")
+
+// runDrift compares the schemas of two tenants, or of two directories of saved describe files,
+// and writes the differences to w.
+func runDrift(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("zuora-gen drift", flag.ContinueOnError)
+	from := flags.String("from", "", "base URL of the reference tenant, such as the sandbox, or a directory of saved describe XML files")
+	to := flags.String("to", "", "base URL of the compared tenant, such as production, or a directory of saved describe XML files")
+	fromEnv := flags.String("from-env", "ZUORA", "prefix of the _CLIENT_ID and _CLIENT_SECRET environment variables of the reference tenant")
+	toEnv := flags.String("to-env", "ZUORA", "prefix of the _CLIENT_ID and _CLIENT_SECRET environment variables of the compared tenant")
+	objects := flags.String("objects", "", "comma separated objects to compare, defaults to every object of the object model")
+	asJSON := flags.Bool("json", false, "write the differences as JSON")
+	timeout := flags.Duration("timeout", 10*time.Minute, "how long describing both tenants may take")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *from == "" || *to == "" {
+		return errors.New("drift needs both -from and -to")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	fromSchema, err := loadSchema(ctx, *from, *fromEnv, splitNames(*objects))
+
+	if err != nil {
+		return err
+	}
+
+	toSchema, err := loadSchema(ctx, *to, *toEnv, splitNames(*objects))
+
+	if err != nil {
+		return err
+	}
+
+	diff := zuora.CompareSchemas(fromSchema, toSchema)
+
+	if *asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(diff); err != nil {
+			return err
+		}
+	} else {
+		writeDiff(w, diff)
+	}
+
+	if !diff.Empty() {
+		return errDrift
+	}
+
+	return nil
+}
+
+// loadSchema describes the tenant at source when it is a URL, or reads the describe files of the directory source.
+func loadSchema(ctx context.Context, source, envPrefix string, names []string) (zuora.Schema, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		descriptions, err := readDescriptions(source, names)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return zuora.NewSchema(descriptions...), nil
+	}
+
+	objects := []zuora.ObjecName{}
+	for _, name := range names {
+		objects = append(objects, zuora.ObjecName(name))
+	}
+
+	schema, err := newAPI(source, envPrefix).V1.DescribeService.Schema(ctx, objects...)
+
+	if err != nil {
+		return nil, fmt.Errorf("describing %v: %v", source, err)
+	}
+
+	return schema, nil
+}
+
+// writeDiff writes one line per difference: + for added, - for removed and ~ for changed.
+func writeDiff(w io.Writer, diff zuora.SchemaDiff) {
+	if diff.Empty() {
+		fmt.Fprintln(w, "No differences")
+		return
+	}
+
+	for _, object := range diff.Objects {
+		fmt.Fprintf(w, "%v %v (object)\n", changeSymbol(object.Change), object.Object)
+	}
+
+	for _, field := range diff.Fields {
+		properties := []string{}
+
+		for _, property := range field.Properties {
+			properties = append(properties, fmt.Sprintf("%v %q -> %q", property.Property, property.From, property.To))
+		}
+
+		if len(properties) > 0 {
+			fmt.Fprintf(w, "%v %v.%v: %v\n", changeSymbol(field.Change), field.Object, field.Field, strings.Join(properties, ", "))
+		} else {
+			fmt.Fprintf(w, "%v %v.%v\n", changeSymbol(field.Change), field.Object, field.Field)
+		}
+	}
+}
+
+func changeSymbol(change zuora.Change) string {
+	switch change {
+	case zuora.ChangeAdded:
+		return "+"
+	case zuora.ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyeomans/zuora"
+)
+
+func TestRunDrift(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zuora-drift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sandbox, production := filepath.Join(dir, "sandbox"), filepath.Join(dir, "production")
+	os.Mkdir(sandbox, 0755)
+	os.Mkdir(production, 0755)
+	ioutil.WriteFile(filepath.Join(sandbox, "Invoice.xml"), []byte(invoiceDescription), 0644)
+	ioutil.WriteFile(filepath.Join(production, "Invoice.xml"), []byte(strings.Replace(invoiceDescription, "<maxlength>50</maxlength>", "<maxlength>80</maxlength>", 1)), 0644)
+
+	var out bytes.Buffer
+
+	if err := runDrift([]string{"-from", sandbox, "-to", sandbox}, &out); err != nil || out.String() != "No differences\n" {
+		t.Errorf("runDrift() = %q, %v, want no differences", out.String(), err)
+	}
+
+	out.Reset()
+	if err := runDrift([]string{"-from", sandbox, "-to", production}, &out); err != errDrift || out.String() != "~ Invoice.region__c: maxlength \"50\" -> \"80\"\n" {
+		t.Errorf("runDrift() = %q, %v", out.String(), err)
+	}
+
+	out.Reset()
+	if err := runDrift([]string{"-from", sandbox, "-to", production, "-json"}, &out); err != errDrift {
+		t.Errorf("runDrift() = %v, want errDrift", err)
+	}
+
+	diff := zuora.SchemaDiff{}
+	if err := json.Unmarshal(out.Bytes(), &diff); err != nil || len(diff.Fields) != 1 || diff.Fields[0].Properties[0].To != "80" {
+		t.Errorf("runDrift() JSON = %s, %v", out.String(), err)
+	}
+
+	if err := runDrift([]string{"-from", sandbox}, &out); err == nil || err == errDrift {
+		t.Errorf("runDrift() = %v, want an error without -to", err)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hyeomans/zuora"
+)
+
+// initialisms are written in upper case in Go names, as golint expects.
+var initialisms = map[string]string{
+	"Id":  "ID",
+	"Api": "API",
+	"Url": "URL",
+}
+
+// generate returns the gofmt'd source of a struct holding the fields of object.
+// Fields are sorted by name so regenerating from the same metadata gives the same file.
+func generate(packageName string, object *zuora.ObjectDescription) ([]byte, error) {
+	fields := append([]zuora.FieldDescription{}, object.Fields...)
+	sort.Slice(fields, func(i, j int) bool {
+		if a, b := strings.ToLower(fields[i].Name), strings.ToLower(fields[j].Name); a != b {
+			return a < b
+		}
+
+		return fields[i].Name < fields[j].Name
+	})
+
+	imports := map[string]bool{}
+	typeName := goName(object.Name)
+	used := map[string]bool{}
+
+	var body bytes.Buffer
+
+	fmt.Fprintf(&body, "// %v %v\n", typeName, sentence(object.Label, object.Name))
+	fmt.Fprintf(&body, "type %v struct {\n", typeName)
+
+	for _, field := range fields {
+		name := goName(field.Name)
+
+		for i := 2; used[name]; i++ {
+			name = goName(field.Name) + strconv.Itoa(i)
+		}
+
+		used[name] = true
+		fieldType, importPath := goType(field.Type)
+
+		if importPath != "" {
+			imports[importPath] = true
+		}
+
+		fmt.Fprintf(&body, "// %v %v\n", name, fieldComment(field))
+		fmt.Fprintf(&body, "%v *%v `json:\"%v,omitempty\"`\n", name, fieldType, field.Name)
+	}
+
+	body.WriteString("}\n")
+
+	var source bytes.Buffer
+
+	fmt.Fprintf(&source, "// Code generated by zuora-gen from the %v describe metadata. DO NOT EDIT.\n\n", object.Name)
+	fmt.Fprintf(&source, "package %v\n\n", packageName)
+
+	if len(imports) > 0 {
+		standard, external := []string{}, []string{}
+
+		for path := range imports {
+			if strings.Contains(path, ".") {
+				external = append(external, strconv.Quote(path))
+			} else {
+				standard = append(standard, strconv.Quote(path))
+			}
+		}
+
+		sort.Strings(standard)
+		sort.Strings(external)
+
+		groups := []string{}
+		for _, group := range [][]string{standard, external} {
+			if len(group) > 0 {
+				groups = append(groups, strings.Join(group, "\n"))
+			}
+		}
+
+		fmt.Fprintf(&source, "import (\n%v\n)\n\n", strings.Join(groups, "\n\n"))
+	}
+
+	source.Write(body.Bytes())
+
+	formatted, err := format.Source(source.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("generated code for %v is invalid: %v", object.Name, err)
+	}
+
+	return formatted, nil
+}
+
+// goType maps a Zuora field type to a Go type and the package it needs.
+func goType(fieldType string) (string, string) {
+	switch strings.ToLower(fieldType) {
+	case "boolean":
+		return "bool", ""
+	case "integer":
+		return "int", ""
+	case "long":
+		return "int64", ""
+	case "decimal":
+		// json.Number keeps amounts exact, convert them with a decimal library when needed.
+		return "json.Number", "encoding/json"
+	case "date":
+		return "zuora.Date", "github.com/hyeomans/zuora"
+	case "datetime":
+		return "time.Time", "time"
+	default:
+		return "string", ""
+	}
+}
+
+// fieldComment describes a field from its label and flags.
+func fieldComment(field zuora.FieldDescription) string {
+	details := []string{}
+
+	if field.Custom {
+		details = append(details, "custom field")
+	}
+
+	if field.Required {
+		details = append(details, "required")
+	}
+
+	if !field.Createable && !field.Updateable {
+		details = append(details, "read only")
+	} else if !field.Updateable {
+		details = append(details, "cannot be updated")
+	}
+
+	if field.MaxLength > 0 {
+		details = append(details, fmt.Sprintf("at most %v characters", field.MaxLength))
+	}
+
+	comment := sentence(field.Label, field.Name)
+
+	if len(details) > 0 {
+		comment = fmt.Sprintf("%v, %v.", strings.TrimSuffix(comment, "."), strings.Join(details, ", "))
+	}
+
+	if len(field.Options) > 0 {
+		comment += " One of " + strings.Join(field.Options, ", ") + "."
+	}
+
+	return comment
+}
+
+// sentence returns label, or name when Zuora has no label, ending with a period.
+func sentence(label, name string) string {
+	label = strings.Join(strings.Fields(label), " ")
+
+	if label == "" {
+		label = name
+	}
+
+	if !strings.HasSuffix(label, ".") {
+		label += "."
+	}
+
+	return label
+}
+
+// goName turns a Zuora name such as AccountId or region__c into an exported Go name such as AccountID or Region__c.
+func goName(name string) string {
+	var b strings.Builder
+
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || (unicode.IsDigit(r) && i > 0):
+			b.WriteRune(r)
+		case unicode.IsDigit(r):
+			b.WriteString("X" + string(r))
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	runes := []rune(b.String())
+
+	if len(runes) == 0 {
+		return "X"
+	}
+
+	runes[0] = unicode.ToUpper(runes[0])
+	result := string(runes)
+
+	for word, initialism := range initialisms {
+		result = replaceWord(result, word, initialism)
+	}
+
+	return result
+}
+
+// replaceWord replaces word when it is a whole word of the camel case name: followed by
+// the end of the name, an upper case letter or an underscore.
+func replaceWord(name, word, replacement string) string {
+	var b strings.Builder
+
+	for {
+		i := strings.Index(name, word)
+
+		if i < 0 {
+			b.WriteString(name)
+			return b.String()
+		}
+
+		rest := name[i+len(word):]
+
+		if rest == "" || unicode.IsUpper(rune(rest[0])) || rest[0] == '_' {
+			b.WriteString(name[:i] + replacement)
+		} else {
+			b.WriteString(name[:i+len(word)])
+		}
+
+		name = rest
+	}
+}
+
+// fileName turns an object name such as InvoiceItem into invoice_item.go.
+func fileName(objectName string) string {
+	var b strings.Builder
+	runes := []rune(objectName)
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteRune('_')
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String() + ".go"
+}
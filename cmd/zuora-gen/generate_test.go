@@ -0,0 +1,125 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyeomans/zuora"
+)
+
+const invoiceDescription = `<object>
+  <name>Invoice</name>
+  <label>Invoice</label>
+  <fields>
+    <field><name>Id</name><label>Invoice ID</label><selectable>true</selectable><createable>false</createable><updateable>false</updateable><required>false</required><type>text</type><maxlength>32</maxlength></field>
+    <field><name>AccountId</name><label>Account ID</label><selectable>true</selectable><createable>true</createable><updateable>false</updateable><required>true</required><type>text</type><maxlength>32</maxlength></field>
+    <field><name>Amount</name><label>Amount</label><selectable>true</selectable><createable>false</createable><updateable>false</updateable><type>decimal</type></field>
+    <field><name>InvoiceDate</name><label>Invoice Date</label><selectable>true</selectable><createable>true</createable><updateable>true</updateable><type>date</type></field>
+    <field><name>UpdatedDate</name><label>Updated Date</label><selectable>true</selectable><createable>false</createable><updateable>false</updateable><type>datetime</type></field>
+    <field><name>Status</name><label>Status</label><createable>false</createable><updateable>true</updateable><type>picklist</type><options><option>Draft</option><option>Posted</option></options></field>
+    <field><name>IncludesUsage</name><label>Includes Usage</label><createable>true</createable><updateable>true</updateable><type>boolean</type></field>
+    <field><name>region__c</name><label>Sales Region</label><createable>true</createable><updateable>true</updateable><custom>true</custom><type>text</type><maxlength>50</maxlength></field>
+  </fields>
+</object>`
+
+func TestGenerate(t *testing.T) {
+	description, err := zuora.ParseObjectDescription([]byte(invoiceDescription))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := generate("zuoratypes", description)
+	if err != nil {
+		t.Fatalf("generate() = %v", err)
+	}
+
+	code := string(source)
+
+	for _, want := range []string{
+		"// Code generated by zuora-gen from the Invoice describe metadata. DO NOT EDIT.",
+		"package zuoratypes",
+		"import (\n\t\"encoding/json\"\n\t\"time\"\n\n\t\"github.com/hyeomans/zuora\"\n)",
+		"// Invoice Invoice.\ntype Invoice struct {",
+		"\t// AccountID Account ID, required, cannot be updated, at most 32 characters.\n\tAccountID *string `json:\"AccountId,omitempty\"`",
+		"\tAmount *json.Number `json:\"Amount,omitempty\"`",
+		"\tID *string `json:\"Id,omitempty\"`",
+		"\tInvoiceDate *zuora.Date `json:\"InvoiceDate,omitempty\"`",
+		"\tIncludesUsage *bool `json:\"IncludesUsage,omitempty\"`",
+		"\t// Region__c Sales Region, custom field, at most 50 characters.\n\tRegion__c *string `json:\"region__c,omitempty\"`",
+		"\t// Status Status. One of Draft, Posted.",
+		"\tUpdatedDate *time.Time `json:\"UpdatedDate,omitempty\"`",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generate() is missing %q in:\n%v", want, code)
+		}
+	}
+
+	if strings.Index(code, "AccountID") > strings.Index(code, "UpdatedDate") {
+		t.Errorf("generate() should sort fields by name:\n%v", code)
+	}
+
+	description.Fields[0], description.Fields[7] = description.Fields[7], description.Fields[0]
+	again, _ := generate("zuoratypes", description)
+
+	if string(again) != code {
+		t.Errorf("generate() should not depend on the order of the describe fields")
+	}
+}
+
+func TestGoNames(t *testing.T) {
+	names := map[string]string{
+		"AccountId":      "AccountID",
+		"Id":             "ID",
+		"IdentityNumber": "IdentityNumber",
+		"ApiUrl":         "APIURL",
+		"region__c":      "Region__c",
+		"My-Field":       "My_Field",
+		"3DSecure":       "X3DSecure",
+	}
+
+	for name, want := range names {
+		if got := goName(name); got != want {
+			t.Errorf("goName(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	files := map[string]string{
+		"Account":      "account.go",
+		"InvoiceItem":  "invoice_item.go",
+		"FXCustomRate": "fx_custom_rate.go",
+	}
+
+	for name, want := range files {
+		if got := fileName(name); got != want {
+			t.Errorf("fileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRunFromXML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zuora-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "describe"), filepath.Join(dir, "zuora-types")
+	os.Mkdir(in, 0755)
+	ioutil.WriteFile(filepath.Join(in, "Invoice.xml"), []byte(invoiceDescription), 0644)
+
+	if err := run([]string{"-xml", in, "-out", out}); err != nil {
+		t.Fatalf("run() = %v", err)
+	}
+
+	source, err := ioutil.ReadFile(filepath.Join(out, "invoice.go"))
+	if err != nil || !strings.Contains(string(source), "package zuoratypes") {
+		t.Errorf("run() wrote %s, %v", source, err)
+	}
+
+	if err := run([]string{"-xml", in, "-out", out, "-objects", "Invoice,Account"}); err == nil || !strings.Contains(err.Error(), "account") {
+		t.Errorf("run() = %v, want an error for the missing Account file", err)
+	}
+}
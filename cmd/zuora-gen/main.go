@@ -0,0 +1,210 @@
+// Command zuora-gen generates Go types for the objects of a Zuora tenant, including its custom fields,
+// from describe metadata. The metadata is read live from the tenant or from saved XML files:
+//
+//	ZUORA_CLIENT_ID=... ZUORA_CLIENT_SECRET=... zuora-gen -url https://rest.apisandbox.zuora.com -objects Account,Invoice -out ./zuoratypes
+//	zuora-gen -xml ./describe -out ./zuoratypes
+//
+// Saved files hold the response of /v1/describe/{object}, one object per file. The output is
+// deterministic, so generated files can be committed and custom field changes reviewed as diffs.
+//
+// The drift subcommand compares the objects of two tenants, or of two directories of saved files,
+// and lists the fields added, removed or changed in the second one:
+//
+//	ZUORA_SANDBOX_CLIENT_ID=... ZUORA_SANDBOX_CLIENT_SECRET=... ZUORA_CLIENT_ID=... ZUORA_CLIENT_SECRET=... \
+//		zuora-gen drift -from https://rest.apisandbox.zuora.com -from-env ZUORA_SANDBOX -to https://rest.zuora.com -json
+//
+// It exits with status 2 when the schemas differ, so it can gate CI pipelines, and 1 on errors.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyeomans/zuora"
+)
+
+func main() {
+	var err error
+
+	if len(os.Args) > 1 && os.Args[1] == "drift" {
+		err = runDrift(os.Args[2:], os.Stdout)
+	} else {
+		err = run(os.Args[1:])
+	}
+
+	if err == errDrift {
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "zuora-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("zuora-gen", flag.ContinueOnError)
+	xmlDir := flags.String("xml", "", "directory of saved describe XML files, instead of describing the tenant live")
+	baseURL := flags.String("url", os.Getenv("ZUORA_URL"), "base URL of the tenant, defaults to $ZUORA_URL")
+	objects := flags.String("objects", "", "comma separated objects to generate, defaults to every object")
+	out := flags.String("out", ".", "directory the files are written to")
+	packageName := flags.String("package", "", "package name of the generated files, defaults to the name of the out directory")
+	timeout := flags.Duration("timeout", 5*time.Minute, "how long describing the tenant may take")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *packageName == "" {
+		absolute, err := filepath.Abs(*out)
+
+		if err != nil {
+			return err
+		}
+
+		*packageName = strings.ToLower(strings.Replace(filepath.Base(absolute), "-", "", -1))
+	}
+
+	var descriptions []*zuora.ObjectDescription
+	var err error
+
+	if *xmlDir != "" {
+		descriptions, err = readDescriptions(*xmlDir, splitNames(*objects))
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+
+		descriptions, err = describe(ctx, newAPI(*baseURL, "ZUORA"), splitNames(*objects))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+
+	for _, description := range descriptions {
+		source, err := generate(*packageName, description)
+
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(*out, fileName(description.Name)), source, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newAPI authenticates with the environment variables envPrefix_CLIENT_ID and envPrefix_CLIENT_SECRET,
+// such as ZUORA_CLIENT_ID and ZUORA_CLIENT_SECRET.
+func newAPI(baseURL, envPrefix string) *zuora.API {
+	return zuora.NewAPI(
+		zuora.WithBaseURL(baseURL),
+		zuora.WithCredentialSource(zuora.EnvCredentials(envPrefix+"_CLIENT_ID", envPrefix+"_CLIENT_SECRET")),
+		zuora.WithRetryPolicy(zuora.DefaultRetryPolicy()),
+	)
+}
+
+// describe returns the description of every object in names, or of every describable object when names is empty.
+func describe(ctx context.Context, api *zuora.API, names []string) ([]*zuora.ObjectDescription, error) {
+	if len(names) == 0 {
+		objects, err := api.V1.DescribeService.Objects(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			names = append(names, object.Name)
+		}
+
+		sort.Strings(names)
+	}
+
+	descriptions := []*zuora.ObjectDescription{}
+
+	for _, name := range names {
+		description, err := api.V1.DescribeService.Object(ctx, zuora.ObjecName(name))
+
+		if err != nil {
+			return nil, fmt.Errorf("describing %v: %v", name, err)
+		}
+
+		descriptions = append(descriptions, description)
+	}
+
+	return descriptions, nil
+}
+
+// readDescriptions parses the XML files of dir, keeping the objects in names when it is not empty.
+func readDescriptions(dir string, names []string) ([]*zuora.ObjectDescription, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[strings.ToLower(name)] = true
+	}
+
+	descriptions := []*zuora.ObjectDescription{}
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		description, err := zuora.ParseObjectDescription(data)
+
+		if err != nil {
+			return nil, fmt.Errorf("reading %v: %v", path, err)
+		}
+
+		if len(names) == 0 || wanted[strings.ToLower(description.Name)] {
+			delete(wanted, strings.ToLower(description.Name))
+			descriptions = append(descriptions, description)
+		}
+	}
+
+	if len(wanted) > 0 {
+		missing := []string{}
+		for name := range wanted {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+
+		return nil, fmt.Errorf("no describe file found for %v in %v", strings.Join(missing, ", "), dir)
+	}
+
+	return descriptions, nil
+}
+
+func splitNames(list string) []string {
+	names := []string{}
+
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
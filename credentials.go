@@ -0,0 +1,109 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Credentials identify a Zuora client. ClientID and ClientSecret hold the OAuth client,
+// the basic auth username and password, or the API access key ID and secret access key,
+// depending on the auth provider they are given to.
+type Credentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// CredentialSource provides the current credentials. Auth providers consult it whenever they
+// authenticate, so rotated credentials are picked up without rebuilding the API.
+type CredentialSource interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// StaticCredentials returns a CredentialSource that never changes.
+func StaticCredentials(clientID, clientSecret string) CredentialSource {
+	return staticCredentials{ClientID: clientID, ClientSecret: clientSecret}
+}
+
+type staticCredentials Credentials
+
+func (s staticCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+// EnvCredentials returns a CredentialSource reading the environment variables
+// clientIDVar and clientSecretVar every time.
+func EnvCredentials(clientIDVar, clientSecretVar string) CredentialSource {
+	return envCredentials{clientIDVar: clientIDVar, clientSecretVar: clientSecretVar}
+}
+
+type envCredentials struct {
+	clientIDVar     string
+	clientSecretVar string
+}
+
+func (e envCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	credentials := Credentials{ClientID: os.Getenv(e.clientIDVar), ClientSecret: os.Getenv(e.clientSecretVar)}
+
+	if credentials.ClientID == "" || credentials.ClientSecret == "" {
+		return Credentials{}, fmt.Errorf("environment variables %v and %v must be set", e.clientIDVar, e.clientSecretVar)
+	}
+
+	return credentials, nil
+}
+
+// FileCredentials is a CredentialSource reading a JSON file such as
+// {"client_id": "...", "client_secret": "..."}, for example a mounted secret.
+// The file is read again whenever it changes.
+type FileCredentials struct {
+	path string
+
+	mu          sync.Mutex
+	modTime     time.Time
+	size        int64
+	credentials Credentials
+}
+
+// NewFileCredentials returns a FileCredentials reading path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Credentials returns the credentials in the file, reading it again when it changed since the last call.
+func (f *FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	info, err := os.Stat(f.path)
+
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.credentials.ClientID != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.credentials, nil
+	}
+
+	data, err := ioutil.ReadFile(f.path)
+
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials := Credentials{}
+
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return Credentials{}, fmt.Errorf("error while Unmarshal credentials file %v: %v", f.path, err)
+	}
+
+	if credentials.ClientID == "" || credentials.ClientSecret == "" {
+		return Credentials{}, fmt.Errorf("credentials file %v must set client_id and client_secret", f.path)
+	}
+
+	f.credentials, f.modTime, f.size = credentials, info.ModTime(), info.Size()
+	return credentials, nil
+}
@@ -0,0 +1,80 @@
+package zuora
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOAuthHeadersCredentialRotation(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "zuora")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		req.ParseForm()
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"access_token": "` + req.Form.Get("client_id") + `", "token_type": "fake", "expires_in": 3600, "scope": "all", "jti": ""}`))
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(dir, "credentials.json")
+	write := func(clientID string, modTime time.Time) {
+		if err := ioutil.WriteFile(path, []byte(`{"client_id": "`+clientID+`", "client_secret": "secret"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	write("oldClientID", time.Now().Add(-time.Hour))
+	oauthHeader := NewOAuthHeader(mockServer.Client(), &MemoryTokenStore{}, "", "", mockServer.URL, OAuthCredentials(NewFileCredentials(path)))
+
+	for _, want := range []string{"Bearer oldClientID", "Bearer oldClientID", "Bearer newClientID"} {
+		if want == "Bearer newClientID" {
+			write("newClientID", time.Now())
+		}
+
+		got, err := oauthHeader.AuthHeaders(ctx)
+
+		if err != nil {
+			t.Errorf("OAuthHeader.AuthHeaders() returned an error: %v", err)
+		}
+
+		if got != want {
+			t.Errorf("OAuthHeader.AuthHeaders() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	os.Setenv("ZUORA_TEST_CLIENT_ID", "testClientID")
+	os.Setenv("ZUORA_TEST_CLIENT_SECRET", "testClientSecret")
+	defer os.Unsetenv("ZUORA_TEST_CLIENT_ID")
+	defer os.Unsetenv("ZUORA_TEST_CLIENT_SECRET")
+
+	authHeaders := NewBasicAuthHeaderFromSource(EnvCredentials("ZUORA_TEST_CLIENT_ID", "ZUORA_TEST_CLIENT_SECRET"))
+	want := "Basic dGVzdENsaWVudElEOnRlc3RDbGllbnRTZWNyZXQ="
+	got, err := authHeaders.AuthHeaders(context.Background())
+
+	if err != nil {
+		t.Errorf("AuthHeaders() return an error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("AuthHeaders() = %q, want %q", got, want)
+	}
+
+	os.Unsetenv("ZUORA_TEST_CLIENT_SECRET")
+
+	if _, err := authHeaders.AuthHeaders(context.Background()); err == nil {
+		t.Error("AuthHeaders() wanted an error for a missing variable but got nil")
+	}
+}
@@ -0,0 +1,227 @@
+package zuora
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CSVDecoder reads the rows of a CSV file, such as the file of an export, one at a time.
+//
+//	rows := zuora.NewCSVDecoder(file)
+//
+//	for rows.Next() {
+//		item := InvoiceItem{}
+//
+//		if err := rows.Decode(&item); err != nil {
+//			return err
+//		}
+//	}
+//
+//	if err := rows.Err(); err != nil {
+//		return err
+//	}
+type CSVDecoder struct {
+	reader  *csv.Reader
+	header  []string
+	columns map[string]int
+	row     []string
+	err     error
+}
+
+// NewCSVDecoder returns a CSVDecoder reading r. The first row is the header.
+func NewCSVDecoder(r io.Reader) *CSVDecoder {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	return &CSVDecoder{reader: reader}
+}
+
+// Header returns the column names, once Next was called.
+func (d *CSVDecoder) Header() []string {
+	return d.header
+}
+
+// Next moves to the next row. It returns false at the end of the file or on error, see Err.
+func (d *CSVDecoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+
+	if d.header == nil {
+		if d.header, d.err = d.reader.Read(); d.err != nil {
+			return false
+		}
+
+		d.columns = csvColumns(d.header)
+	}
+
+	if d.row, d.err = d.reader.Read(); d.err != nil {
+		return false
+	}
+
+	return true
+}
+
+// Decode sets out from the current row. out is a pointer to a map[string]string keyed by
+// column name, or to a struct whose fields are matched to columns by the names SelectFor
+// selects. A field named Id matches a column such as InvoiceItem.Id when no other
+// column ends with Id.
+func (d *CSVDecoder) Decode(out interface{}) error {
+	if m, ok := out.(*map[string]string); ok {
+		*m = make(map[string]string, len(d.header))
+
+		for i, name := range d.header {
+			if i < len(d.row) {
+				(*m)[name] = d.row[i]
+			}
+		}
+
+		return nil
+	}
+
+	v := reflect.ValueOf(out)
+
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("csv: Decode needs a pointer to a struct or a map[string]string, got %T", out)
+	}
+
+	fields, err := zoqlFields(v.Type())
+
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		column, ok := d.columns[strings.ToLower(field.name)]
+
+		if !ok || column >= len(d.row) {
+			continue
+		}
+
+		if err := setCSVField(fieldByIndex(v.Elem(), field.index), d.row[column]); err != nil {
+			return fmt.Errorf("csv: column %v: %v", d.header[column], err)
+		}
+	}
+
+	return nil
+}
+
+// DecodeAll appends every remaining row to out, a pointer to a slice of structs,
+// of pointers to structs or of map[string]string.
+func (d *CSVDecoder) DecodeAll(out interface{}) error {
+	slice := reflect.ValueOf(out)
+
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csv: DecodeAll needs a pointer to a slice, got %T", out)
+	}
+
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	for d.Next() {
+		isPtr := elemType.Kind() == reflect.Ptr
+		elem := reflect.New(elemType)
+
+		if isPtr {
+			elem = reflect.New(elemType.Elem())
+		}
+
+		if err := d.Decode(elem.Interface()); err != nil {
+			return err
+		}
+
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	return d.Err()
+}
+
+// Err returns the error that stopped the decoder, if any.
+func (d *CSVDecoder) Err() error {
+	if d.err == io.EOF {
+		return nil
+	}
+
+	return d.err
+}
+
+// csvColumns indexes columns by lower case name and, when not ambiguous,
+// by the name after the object prefix, so Id finds InvoiceItem.Id.
+func csvColumns(header []string) map[string]int {
+	columns := map[string]int{}
+	suffixes := map[string][]int{}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		columns[name] = i
+
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			suffixes[name[dot+1:]] = append(suffixes[name[dot+1:]], i)
+		}
+	}
+
+	for suffix, indexes := range suffixes {
+		if _, ok := columns[suffix]; !ok && len(indexes) == 1 {
+			columns[suffix] = indexes[0]
+		}
+	}
+
+	return columns
+}
+
+func setCSVField(v reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %v", v.Type())
+	}
+
+	return nil
+}
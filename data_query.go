@@ -0,0 +1,145 @@
+package zuora
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type dataQueryService struct {
+	client *client
+}
+
+func newDataQueryService(client *client) *dataQueryService {
+	return &dataQueryService{
+		client: client,
+	}
+}
+
+// Submit starts a Data Query job running an SQL query.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/POST_DataQueryJob
+func (t *dataQueryService) Submit(ctx context.Context, jobRequest DataQueryJobRequest) (DataQueryJob, error) {
+	payload := dataQueryJobPayload{
+		Query:           jobRequest.Query,
+		OutputFormat:    jobRequest.OutputFormat,
+		Compression:     jobRequest.Compression,
+		ColumnSeparator: jobRequest.ColumnSeparator,
+		ReadDeleted:     jobRequest.ReadDeleted,
+		Output:          dataQueryOutput{Target: "S3"},
+	}
+
+	if payload.OutputFormat == "" {
+		payload.OutputFormat = DataQueryFormatCSV
+	}
+
+	if payload.Compression == "" {
+		payload.Compression = DataQueryCompressionNone
+	}
+
+	return t.job(ctx, request{method: http.MethodPost, path: "/query/jobs", body: payload})
+}
+
+// Get returns the job jobID.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/GET_DataQueryJob
+func (t *dataQueryService) Get(ctx context.Context, jobID string) (DataQueryJob, error) {
+	path := fmt.Sprintf("/query/jobs/%v", url.PathEscape(jobID))
+	return t.job(ctx, request{method: http.MethodGet, path: path})
+}
+
+// List returns the latest jobs.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/GET_DataQueryJobs
+func (t *dataQueryService) List(ctx context.Context, options DataQueryListOptions) ([]DataQueryJob, error) {
+	query := url.Values{}
+
+	if options.PageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(options.PageSize))
+	}
+
+	if options.Status != "" {
+		query.Set("queryStatus", string(options.Status))
+	}
+
+	path := "/query/jobs"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	jsonResponse := dataQueryJobsResponse{}
+
+	if err := t.client.doJSON(ctx, request{method: http.MethodGet, path: path}, &jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Data, nil
+}
+
+// Cancel cancels the job jobID.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/DELETE_DataQueryJob
+func (t *dataQueryService) Cancel(ctx context.Context, jobID string) (DataQueryJob, error) {
+	path := fmt.Sprintf("/query/jobs/%v", url.PathEscape(jobID))
+	return t.job(ctx, request{method: http.MethodDelete, path: path})
+}
+
+// Wait polls the job jobID, waiting longer between every poll as set by WithPollInterval,
+// until it is completed. A failed or cancelled job returns an error along with the job.
+func (t *dataQueryService) Wait(ctx context.Context, jobID string) (DataQueryJob, error) {
+	var job DataQueryJob
+
+	err := t.client.poll(ctx, func() (bool, error) {
+		var err error
+
+		if job, err = t.Get(ctx, jobID); err != nil {
+			return false, err
+		}
+
+		switch job.QueryStatus {
+		case DataQueryStatusCompleted:
+			return true, nil
+		case DataQueryStatusFailed, DataQueryStatusCancelled:
+			return false, &JobError{Job: "data query job", ID: jobID, Status: string(job.QueryStatus), Message: job.ErrorMessage}
+		}
+
+		return false, nil
+	})
+
+	return job, err
+}
+
+// Download streams the results of a completed job from its DataFile URL, without
+// holding them in memory. The caller must close the returned reader.
+func (t *dataQueryService) Download(ctx context.Context, job DataQueryJob) (io.ReadCloser, error) {
+	if job.DataFile == "" {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("data query job %v has no results, its status is %v", job.ID, job.QueryStatus)}
+	}
+
+	return t.client.stream(ctx, request{method: http.MethodGet, path: job.DataFile, external: true})
+}
+
+// Run submits the job, waits for its completion and streams its results.
+// The caller must close the returned reader.
+func (t *dataQueryService) Run(ctx context.Context, jobRequest DataQueryJobRequest) (io.ReadCloser, error) {
+	job, err := t.Submit(ctx, jobRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if job, err = t.Wait(ctx, job.ID); err != nil {
+		return nil, err
+	}
+
+	return t.Download(ctx, job)
+}
+
+func (t *dataQueryService) job(ctx context.Context, r request) (DataQueryJob, error) {
+	jsonResponse := dataQueryJobResponse{}
+
+	if err := t.client.doJSON(ctx, r, &jsonResponse); err != nil {
+		return DataQueryJob{}, err
+	}
+
+	return jsonResponse.Data, nil
+}
@@ -0,0 +1,89 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDataQueryServiceRun(t *testing.T) {
+	fileServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if got := req.Header.Get("Authorization"); got != "" {
+			t.Errorf("results were requested with the Authorization header %q", got)
+		}
+
+		rw.Write([]byte("id,name\n1,Acme\n"))
+	}))
+	defer fileServer.Close()
+
+	polls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == "/query/jobs":
+			payload := dataQueryJobPayload{}
+			json.NewDecoder(req.Body).Decode(&payload)
+
+			if payload.Query != "select id, name from account" || payload.OutputFormat != DataQueryFormatCSV || payload.Compression != DataQueryCompressionNone || payload.Output.Target != "S3" {
+				t.Errorf("data query payload = %+v", payload)
+			}
+
+			rw.Write([]byte(`{"data": {"id": "job1", "queryStatus": "accepted"}}`))
+		case req.URL.Path == "/query/jobs/job1":
+			polls++
+
+			if polls < 2 {
+				rw.Write([]byte(`{"data": {"id": "job1", "queryStatus": "in_progress"}}`))
+				return
+			}
+
+			rw.Write([]byte(`{"data": {"id": "job1", "queryStatus": "completed", "dataFile": "` + fileServer.URL + `/results.csv?signature=1"}}`))
+		case req.URL.Path == "/query/jobs":
+			if got := req.URL.Query().Get("queryStatus"); got != "completed" {
+				t.Errorf("queryStatus = %q, want completed", got)
+			}
+
+			rw.Write([]byte(`{"data": [{"id": "job0", "queryStatus": "completed"}]}`))
+		default:
+			t.Errorf("unexpected request to %v %v", req.Method, req.URL)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithPollInterval(time.Millisecond, 5*time.Millisecond))
+	results, err := api.V1.DataQueryService.Run(context.Background(), DataQueryJobRequest{Query: "select id, name from account"})
+
+	if err != nil {
+		t.Fatalf("DataQueryService.Run() returned an error: %v", err)
+	}
+	defer results.Close()
+
+	if data, _ := ioutil.ReadAll(results); string(data) != "id,name\n1,Acme\n" {
+		t.Errorf("DataQueryService.Run() = %q", data)
+	}
+
+	jobs, err := api.V1.DataQueryService.List(context.Background(), DataQueryListOptions{Status: DataQueryStatusCompleted})
+	if err != nil || len(jobs) != 1 || jobs[0].ID != "job0" {
+		t.Errorf("DataQueryService.List() = %+v, %v", jobs, err)
+	}
+}
+
+func TestDataQueryServiceWaitFailed(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"data": {"id": "job1", "queryStatus": "failed", "errorMessage": "invalid query"}}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithPollInterval(time.Millisecond, 5*time.Millisecond))
+	job, err := api.V1.DataQueryService.Wait(context.Background(), "job1")
+	jobErr := &JobError{}
+
+	if !errors.As(err, &jobErr) || jobErr.ID != "job1" || jobErr.Message != "invalid query" || job.QueryStatus != DataQueryStatusFailed {
+		t.Errorf("DataQueryService.Wait() = %+v, %v, want a failed job and a *JobError", job, err)
+	}
+}
@@ -0,0 +1,95 @@
+package zuora
+
+//DataQueryFormat is the format of the results of a Data Query job.
+type DataQueryFormat string
+
+//Data Query output formats.
+const (
+	DataQueryFormatJSON DataQueryFormat = "JSON"
+	DataQueryFormatCSV  DataQueryFormat = "CSV"
+	DataQueryFormatTSV  DataQueryFormat = "TSV"
+	DataQueryFormatDSV  DataQueryFormat = "DSV"
+)
+
+//DataQueryCompression is the compression of the results of a Data Query job.
+type DataQueryCompression string
+
+//Data Query compressions.
+const (
+	DataQueryCompressionNone DataQueryCompression = "NONE"
+	DataQueryCompressionGZIP DataQueryCompression = "GZIP"
+	DataQueryCompressionZIP  DataQueryCompression = "ZIP"
+)
+
+//DataQueryStatus is the status of a Data Query job.
+type DataQueryStatus string
+
+//Data Query statuses, a job is over once it is completed, failed or cancelled.
+const (
+	DataQueryStatusAccepted   DataQueryStatus = "accepted"
+	DataQueryStatusInProgress DataQueryStatus = "in_progress"
+	DataQueryStatusCompleted  DataQueryStatus = "completed"
+	DataQueryStatusFailed     DataQueryStatus = "failed"
+	DataQueryStatusCancelled  DataQueryStatus = "cancelled"
+)
+
+//DataQueryJobRequest describes a Data Query job.
+//More info at: https://knowledgecenter.zuora.com/Central_Platform/Query/Data_Query
+type DataQueryJobRequest struct {
+	// Required. SQL query, which can join any objects.
+	Query string
+	// Format of the results, defaults to DataQueryFormatCSV.
+	OutputFormat DataQueryFormat
+	// Compression of the results, defaults to DataQueryCompressionNone.
+	Compression DataQueryCompression
+	// ColumnSeparator is the single character separating columns with DataQueryFormatDSV.
+	ColumnSeparator string
+	// ReadDeleted queries deleted records instead of current ones.
+	ReadDeleted bool
+}
+
+type dataQueryJobPayload struct {
+	Query           string               `json:"query"`
+	OutputFormat    DataQueryFormat      `json:"outputFormat"`
+	Compression     DataQueryCompression `json:"compression"`
+	ColumnSeparator string               `json:"columnSeparator,omitempty"`
+	ReadDeleted     bool                 `json:"readDeleted"`
+	Output          dataQueryOutput      `json:"output"`
+}
+
+type dataQueryOutput struct {
+	Target string `json:"target"`
+}
+
+type dataQueryJobResponse struct {
+	Data DataQueryJob `json:"data"`
+}
+
+type dataQueryJobsResponse struct {
+	Data []DataQueryJob `json:"data"`
+}
+
+//DataQueryJob is a Data Query job. DataFile is the URL of the results once the job is completed.
+type DataQueryJob struct {
+	ID             string               `json:"id"`
+	Query          string               `json:"query"`
+	QueryStatus    DataQueryStatus      `json:"queryStatus"`
+	OutputFormat   DataQueryFormat      `json:"outputFormat"`
+	Compression    DataQueryCompression `json:"compression"`
+	ReadDeleted    bool                 `json:"readDeleted"`
+	DataFile       string               `json:"dataFile"`
+	OutputRows     int                  `json:"outputRows"`
+	ProcessingTime int                  `json:"processingTime"`
+	ErrorMessage   string               `json:"errorMessage"`
+	CreatedBy      string               `json:"createdBy"`
+	CreatedOn      string               `json:"createdOn"`
+	UpdatedOn      string               `json:"updatedOn"`
+}
+
+//DataQueryListOptions filters the jobs returned by DataQueryService.List.
+type DataQueryListOptions struct {
+	// PageSize is the number of jobs returned, Zuora defaults to 20.
+	PageSize int
+	// Status only returns the jobs in that status.
+	Status DataQueryStatus
+}
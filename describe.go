@@ -4,116 +4,79 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"strings"
 )
 
 type describeService struct {
-	http               Doer
-	authHeaderProvider AuthHeaderProvider
-	baseURL            string
+	client *client
 }
 
-type xmlObject struct {
-	XMLName xml.Name `xml:"object"`
-	Name    string   `xml:"name"`
-	Fields  fields   `xml:"fields"`
-}
-
-type fields struct {
-	XMLName xml.Name `xml:"fields"`
-	Fields  []field  `xml:"field"`
-}
-
-type field struct {
-	XMLName    xml.Name `xml:"field"`
-	Name       string   `xml:"name"`
-	Custom     bool     `xml:"custom"`
-	Label      string   `xml:"label"`
-	Selectable bool     `xml:"selectable"`
-	Createable bool     `xml:"createable"`
-	Updateable bool     `xml:"updateable"`
-	Filterable bool     `xml:"filterable"`
-	Maxlength  string   `xml:"maxlength"`
-	Required   bool     `xml:"required"`
-	Type       string   `xml:"type"`
-}
-
-func newDescribeService(http Doer, authHeaderProvider AuthHeaderProvider, baseURL string) *describeService {
+func newDescribeService(client *client) *describeService {
 	return &describeService{
-		http:               http,
-		authHeaderProvider: authHeaderProvider,
-		baseURL:            baseURL,
+		client: client,
 	}
 }
 
-func (t *describeService) Model(ctx context.Context, objectName ObjecName) (string, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	url := fmt.Sprintf("%v/v1/describe/%v", t.baseURL, objectName)
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// Objects lists every object that can be described in the tenant.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/GET_Describe
+func (t *describeService) Objects(ctx context.Context) ([]ObjectSummary, error) {
+	body, err := t.client.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/v1/describe",
+	})
 
 	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
+		return nil, err
 	}
 
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
+	var objects objectList
 
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
+	if err := xml.Unmarshal(body, &objects); err != nil {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal xml response. Error: %v. XML: %v", err, string(body)), err: err}
 	}
 
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraVersion) != nil {
-		req.Header.Add("zuora-version", ctx.Value(ContextKeyZuoraVersion).(string))
-	}
+	return objects.Objects, nil
+}
 
-	res, err := t.http.Do(req.WithContext(ctx))
-	defer res.Body.Close()
+// Object returns the metadata of objectName, including its custom fields.
+// API Reference: https://www.zuora.com/developer/api-reference/#operation/GET_Describe
+func (t *describeService) Object(ctx context.Context, objectName ObjecName) (*ObjectDescription, error) {
+	body, err := t.client.do(ctx, request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v1/describe/%v", objectName),
+	})
 
 	if err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
+		return nil, err
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
+	return ParseObjectDescription(body)
+}
 
-		if err != nil {
-			return "", responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
+// ParseObjectDescription reads the XML returned by /v1/describe/{object}, for example from a saved file.
+func ParseObjectDescription(data []byte) (*ObjectDescription, error) {
+	description := &ObjectDescription{}
 
-		return "", responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
+	if err := xml.Unmarshal(data, description); err != nil {
+		return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal xml response. Error: %v. XML: %v", err, string(data)), err: err}
 	}
 
-	var objectAsXML xmlObject
+	return description, nil
+}
+
+// Model returns the definition of a Go struct holding the fields of objectName.
+func (t *describeService) Model(ctx context.Context, objectName ObjecName) (string, error) {
+	description, err := t.Object(ctx, objectName)
 
-	if err := xml.Unmarshal(body, &objectAsXML); err != nil {
-		return "", responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
+	if err != nil {
+		return "", err
 	}
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "type %v struct{\n", objectAsXML.Name)
+	fmt.Fprintf(&b, "type %v struct{\n", description.Name)
 
-	for _, field := range objectAsXML.Fields.Fields {
-		fmt.Println("Field types", field.Name, field.Type)
+	for _, field := range description.Fields {
 		currentType := getType(field.Required, field.Type)
 		if field.Required {
 			fmt.Fprintf(&b, "%v\t%v\t`json:\"%v\"`\n", field.Name, currentType, field.Name)
@@ -0,0 +1,116 @@
+package zuora
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const accountDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<object>
+  <name>Account</name>
+  <label>Account</label>
+  <fields>
+    <field>
+      <name>Name</name>
+      <label>Account Name</label>
+      <selectable>true</selectable>
+      <createable>true</createable>
+      <updateable>true</updateable>
+      <filterable>true</filterable>
+      <custom>false</custom>
+      <maxlength>255</maxlength>
+      <required>true</required>
+      <type>text</type>
+      <contexts><context>soap</context><context>export</context></contexts>
+    </field>
+    <field>
+      <name>Status</name>
+      <label>Status</label>
+      <selectable>true</selectable>
+      <createable>true</createable>
+      <updateable>true</updateable>
+      <filterable>true</filterable>
+      <custom>false</custom>
+      <maxlength></maxlength>
+      <required>false</required>
+      <type>picklist</type>
+      <options><option>Draft</option><option>Active</option><option>Canceled</option></options>
+    </field>
+    <field>
+      <name>Region__c</name>
+      <label>Sales Region</label>
+      <selectable>true</selectable>
+      <createable>true</createable>
+      <updateable>false</updateable>
+      <filterable>true</filterable>
+      <custom>true</custom>
+      <maxlength>50</maxlength>
+      <required>false</required>
+      <type>text</type>
+    </field>
+  </fields>
+  <related-objects>
+    <object href="https://rest.zuora.com/v1/describe/Contact">
+      <name>BillToContact</name>
+      <label>Bill To Contact</label>
+    </object>
+  </related-objects>
+</object>`
+
+func TestDescribeObject(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/describe":
+			rw.Write([]byte(`<objects>
+  <object href="https://rest.zuora.com/v1/describe/Account"><name>Account</name><label>Account</label></object>
+  <object href="https://rest.zuora.com/v1/describe/Invoice"><name>Invoice</name><label>Invoice</label></object>
+</objects>`))
+		case "/v1/describe/Account":
+			rw.Write([]byte(accountDescription))
+		default:
+			t.Errorf("unexpected request to %v", req.URL)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	ctx := context.Background()
+
+	objects, err := api.V1.DescribeService.Objects(ctx)
+	if err != nil || len(objects) != 2 || objects[1].Name != "Invoice" || objects[1].Href != "https://rest.zuora.com/v1/describe/Invoice" {
+		t.Errorf("DescribeService.Objects() = %+v, %v", objects, err)
+	}
+
+	account, err := api.V1.DescribeService.Object(ctx, api.ObjectModel.Account)
+	if err != nil {
+		t.Fatalf("DescribeService.Object() = %v", err)
+	}
+
+	if account.Name != "Account" || len(account.Fields) != 3 || len(account.RelatedObjects) != 1 || account.RelatedObjects[0].Name != "BillToContact" {
+		t.Errorf("DescribeService.Object() = %+v", account)
+	}
+
+	name, ok := account.Field("name")
+	if !ok || !name.Required || name.MaxLength != 255 || name.Label != "Account Name" || len(name.Contexts) != 2 {
+		t.Errorf("Field(name) = %+v, %v", name, ok)
+	}
+
+	status, _ := account.Field("Status")
+	if status.MaxLength != 0 || len(status.Options) != 3 || status.Type != "picklist" {
+		t.Errorf("Field(Status) = %+v", status)
+	}
+
+	region, _ := account.Field("Region__c")
+	if !region.Custom || region.Updateable || !region.Createable {
+		t.Errorf("Field(Region__c) = %+v", region)
+	}
+
+	model, err := api.V1.DescribeService.Model(ctx, api.ObjectModel.Account)
+	if err != nil || !strings.Contains(model, "Region__c\t*string\t`json:\"Region__c,omitempty\"`") {
+		t.Errorf("DescribeService.Model() = %v, %v", model, err)
+	}
+}
@@ -0,0 +1,61 @@
+package zuora
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// ObjectDescription is the metadata of a Zuora object, including the custom fields of the tenant,
+// as returned by DescribeService.Object.
+type ObjectDescription struct {
+	XMLName        xml.Name           `xml:"object"`
+	Name           string             `xml:"name"`
+	Label          string             `xml:"label"`
+	Fields         []FieldDescription `xml:"fields>field"`
+	RelatedObjects []ObjectSummary    `xml:"related-objects>object"`
+}
+
+// Field returns the field named name, ignoring case as Zuora does.
+func (o *ObjectDescription) Field(name string) (FieldDescription, bool) {
+	for _, field := range o.Fields {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+
+	return FieldDescription{}, false
+}
+
+// FieldDescription is the metadata of a single field of an object.
+type FieldDescription struct {
+	Name  string `xml:"name"`
+	Label string `xml:"label"`
+	// Type is the Zuora type of the field, such as text, picklist, boolean, integer, decimal, date or datetime.
+	Type   string `xml:"type"`
+	Custom bool   `xml:"custom"`
+	// Selectable fields can be queried, Filterable fields can be used in where clauses.
+	Selectable bool `xml:"selectable"`
+	Createable bool `xml:"createable"`
+	Updateable bool `xml:"updateable"`
+	Filterable bool `xml:"filterable"`
+	Required   bool `xml:"required"`
+	// MaxLength is the maximum length of text fields, zero when Zuora sets none.
+	MaxLength int `xml:"maxlength"`
+	// Options lists the values allowed by picklist fields.
+	Options []string `xml:"options>option"`
+	// Contexts lists where the field is available, such as soap or export.
+	Contexts []string `xml:"contexts>context"`
+}
+
+// ObjectSummary names an object, either a describable object or an object related to another one.
+type ObjectSummary struct {
+	Name  string `xml:"name"`
+	Label string `xml:"label"`
+	// Href is the describe URL of the object.
+	Href string `xml:"href,attr"`
+}
+
+type objectList struct {
+	XMLName xml.Name        `xml:"objects"`
+	Objects []ObjectSummary `xml:"object"`
+}
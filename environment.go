@@ -0,0 +1,71 @@
+package zuora
+
+import (
+	"strings"
+)
+
+// Environment describes where a Zuora tenant lives. BaseURL is used for every endpoint,
+// unless one of the Routes matches the endpoint path, in which case the route base URL is used.
+// More info at: https://www.zuora.com/developer/api-reference/#section/Introduction/Access-to-the-API
+type Environment struct {
+	Name    string
+	BaseURL string
+	// Routes maps endpoint path prefixes, such as "/v1/action/", to the base URL,
+	// including the port, that serves them. The longest matching prefix wins.
+	Routes map[string]string
+}
+
+// Zuora data centers.
+var (
+	EnvironmentUSProduction       = Environment{Name: "US Production", BaseURL: "https://rest.zuora.com"}
+	EnvironmentUSSandbox          = Environment{Name: "US API Sandbox", BaseURL: "https://rest.apisandbox.zuora.com"}
+	EnvironmentUSCloudProduction  = Environment{Name: "US Cloud Production", BaseURL: "https://rest.na.zuora.com"}
+	EnvironmentUSCloudSandbox     = Environment{Name: "US Cloud API Sandbox", BaseURL: "https://rest.sandbox.na.zuora.com"}
+	EnvironmentUSCentralSandbox   = Environment{Name: "US Central Sandbox", BaseURL: "https://rest.test.zuora.com"}
+	EnvironmentEUProduction       = Environment{Name: "EU Production", BaseURL: "https://rest.eu.zuora.com"}
+	EnvironmentEUSandbox          = Environment{Name: "EU API Sandbox", BaseURL: "https://rest.sandbox.eu.zuora.com"}
+	EnvironmentEUCentralSandbox   = Environment{Name: "EU Central Sandbox", BaseURL: "https://rest.test.eu.zuora.com"}
+	EnvironmentAPACProduction     = Environment{Name: "APAC Production", BaseURL: "https://rest.ap.zuora.com"}
+	EnvironmentAPACSandbox        = Environment{Name: "APAC API Sandbox", BaseURL: "https://rest.sandbox.ap.zuora.com"}
+	EnvironmentAPACCentralSandbox = Environment{Name: "APAC Central Sandbox", BaseURL: "https://rest.test.ap.zuora.com"}
+)
+
+// pceDataPort is the port the Production Copy Environment uses for object and action endpoints.
+const pceDataPort = "19016"
+
+// CustomEnvironment returns an Environment that sends every request to baseURL.
+func CustomEnvironment(baseURL string) Environment {
+	return Environment{Name: "Custom", BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// PCEEnvironment returns the Environment of a Production Copy Environment (PCE) available at baseURL.
+// Object and action endpoints are served on a dedicated port.
+func PCEEnvironment(baseURL string) Environment {
+	baseURL = strings.TrimRight(baseURL, "/")
+	dataURL := baseURL + ":" + pceDataPort
+
+	return Environment{
+		Name:    "Production Copy Environment",
+		BaseURL: baseURL,
+		Routes: map[string]string{
+			"/v1/action/":   dataURL,
+			"/v1/object/":   dataURL,
+			"/v1/invoices/": dataURL,
+		},
+	}
+}
+
+// URL returns the full URL of the endpoint at path.
+func (e Environment) URL(path string) string {
+	baseURL := e.BaseURL
+	longest := -1
+
+	for prefix, routeURL := range e.Routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			baseURL = routeURL
+			longest = len(prefix)
+		}
+	}
+
+	return strings.TrimRight(baseURL, "/") + path
+}
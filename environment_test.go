@@ -0,0 +1,25 @@
+package zuora
+
+import (
+	"testing"
+)
+
+func TestEnvironmentURL(t *testing.T) {
+	pce := PCEEnvironment("https://pce.example.com/")
+
+	for _, tt := range []struct {
+		environment Environment
+		path        string
+		want        string
+	}{
+		{EnvironmentEUSandbox, "/v1/accounts/A0001", "https://rest.sandbox.eu.zuora.com/v1/accounts/A0001"},
+		{CustomEnvironment("https://zuora.example.com/"), "/v1/action/query", "https://zuora.example.com/v1/action/query"},
+		{pce, "/v1/accounts/A0001", "https://pce.example.com/v1/accounts/A0001"},
+		{pce, "/v1/action/create", "https://pce.example.com:19016/v1/action/create"},
+		{pce, "/v1/object/invoice/1", "https://pce.example.com:19016/v1/object/invoice/1"},
+	} {
+		if got := tt.environment.URL(tt.path); got != tt.want {
+			t.Errorf("%v.URL(%q) = %q, want %q", tt.environment.Name, tt.path, got, tt.want)
+		}
+	}
+}
@@ -1,89 +1,188 @@
 package zuora
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
-type responseError struct {
-	isTemporary bool
-	message     string
-}
+// Sentinel errors to use with errors.Is. An *Error matches a sentinel when any of its
+// reasons belongs to the matching category, or when its HTTP status code implies it.
+//
+//	if errors.Is(err, zuora.ErrNotFound) { ... }
+var (
+	ErrAccessDenied     = errors.New("zuora: permission or access denied")
+	ErrAuthFailed       = errors.New("zuora: authentication failed")
+	ErrInvalidFormat    = errors.New("zuora: invalid format or value")
+	ErrUnknownField     = errors.New("zuora: unknown field in request")
+	ErrRequiredField    = errors.New("zuora: missing required field")
+	ErrRuleRestriction  = errors.New("zuora: rule restriction")
+	ErrNotFound         = errors.New("zuora: not found")
+	ErrLocked           = errors.New("zuora: locking contention")
+	ErrInternal         = errors.New("zuora: internal error")
+	ErrRateLimited      = errors.New("zuora: request exceeded limit")
+	ErrMalformedRequest = errors.New("zuora: malformed request")
+	ErrExtension        = errors.New("zuora: extension error")
+)
 
-func (r responseError) Temporary() bool {
-	return r.isTemporary
-}
+// ReasonCategory is the last two digits of a Zuora reason code.
+// More info at: https://www.zuora.com/developer/api-reference/#section/Error-Handling
+type ReasonCategory int
 
-func (r responseError) Error() string {
-	return r.message
-}
+const (
+	//CategoryUnknown Unknown
+	CategoryUnknown ReasonCategory = 0
 
-func isRetryableStatusCode(statusCode int) bool {
-	return http.StatusRequestTimeout == statusCode ||
-		http.StatusTooManyRequests == statusCode ||
-		http.StatusInternalServerError == statusCode ||
-		http.StatusServiceUnavailable == statusCode
-}
+	//CategoryAccessDenied Permission or access denied
+	CategoryAccessDenied ReasonCategory = 10
 
-//ErrorResponse could happen even when you get a 200 response from Zuora.
-//The correct way to parse it, is to look at the Code inside reasons acording to Zuora docs.
-type errorResponse struct {
-	Success   bool   `json:"success"`
-	ProcessID string `json:"processId"`
-	Reasons   []struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"reasons"`
-}
+	//CategoryAuthFailed Authentication failed
+	CategoryAuthFailed ReasonCategory = 11
 
-const (
-	//Unknown
-	unknown = 0
+	//CategoryInvalidFormat Invalid format or value
+	CategoryInvalidFormat ReasonCategory = 20
 
-	//Permission or access denied
-	accessDenied = 10
+	//CategoryUnknownField Unknown field in request
+	CategoryUnknownField ReasonCategory = 21
 
-	//Authentication failed
-	authFailed = 11
+	//CategoryRequiredField Missing required field
+	CategoryRequiredField ReasonCategory = 22
 
-	//Invalid format or value
-	invalidFormat = 20
+	//CategoryRuleRestriction Rule restriction
+	CategoryRuleRestriction ReasonCategory = 30
 
-	//Unknown field in request
-	unknownField = 21
+	//CategoryNotFound Not found
+	CategoryNotFound ReasonCategory = 40
 
-	//Missing required field
-	requiredField = 22
+	//CategoryLockingContention Locking contention
+	CategoryLockingContention ReasonCategory = 50
 
-	//Rule restriction
-	ruleRestriction = 30
+	//CategoryInternalError Internal error
+	CategoryInternalError ReasonCategory = 60
 
-	//Not found
-	notFound = 40
+	//CategoryRequestExceeded Request exceeded limit
+	CategoryRequestExceeded ReasonCategory = 70
 
-	//Locking contention
-	lockingContention = 50
+	//CategoryMalformedRequest Malformed request
+	CategoryMalformedRequest ReasonCategory = 90
 
-	//Internal error
-	internalError = 60
+	//CategoryExtensionError Extension error
+	CategoryExtensionError ReasonCategory = 99
+)
 
-	//Request exceeded limit
-	requestExceeded = 70
+var sentinelCategories = map[error]ReasonCategory{
+	ErrAccessDenied:     CategoryAccessDenied,
+	ErrAuthFailed:       CategoryAuthFailed,
+	ErrInvalidFormat:    CategoryInvalidFormat,
+	ErrUnknownField:     CategoryUnknownField,
+	ErrRequiredField:    CategoryRequiredField,
+	ErrRuleRestriction:  CategoryRuleRestriction,
+	ErrNotFound:         CategoryNotFound,
+	ErrLocked:           CategoryLockingContention,
+	ErrInternal:         CategoryInternalError,
+	ErrRateLimited:      CategoryRequestExceeded,
+	ErrMalformedRequest: CategoryMalformedRequest,
+	ErrExtension:        CategoryExtensionError,
+}
 
-	//Malformed request
-	malformedRequest = 90
+// Reason is a single entry of the reasons array returned by Zuora.
+// Codes are 8 digits long: 3 digits for the object, 3 digits for the field
+// and 2 digits for the category.
+type Reason struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
 
-	//Extension error
-	extensionError = 99
-)
+// ObjectCode returns the first three digits of the code, identifying the object.
+func (r Reason) ObjectCode() int {
+	return r.Code / 100000
+}
+
+// FieldCode returns the middle three digits of the code, identifying the field.
+func (r Reason) FieldCode() int {
+	return r.Code / 100 % 1000
+}
+
+// Category returns the last two digits of the code.
+func (r Reason) Category() ReasonCategory {
+	return ReasonCategory(r.Code % 100)
+}
+
+// ObjectError is an error returned by the object (CRUD) and action endpoints. Unlike reasons,
+// they carry string codes such as INVALID_VALUE.
+type ObjectError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+// Category maps the string code to the matching reason category.
+func (o ObjectError) Category() ReasonCategory {
+	switch strings.TrimPrefix(strings.ToUpper(o.Code), "FNS:") {
+	case "API_DISABLED", "INVALID_LOGIN", "INVALID_SESSION":
+		return CategoryAuthFailed
+	case "INVALID_VALUE", "INVALID_TYPE", "INVALID_VERSION":
+		return CategoryInvalidFormat
+	case "INVALID_FIELD":
+		return CategoryUnknownField
+	case "MISSING_REQUIRED_VALUE":
+		return CategoryRequiredField
+	case "DUPLICATE_VALUE", "CANNOT_DELETE", "MAX_RECORDS_EXCEEDED", "TRANSACTION_FAILED", "TRANSACTION_TERMINATED":
+		return CategoryRuleRestriction
+	case "INVALID_ID":
+		return CategoryNotFound
+	case "LOCK_COMPETITION":
+		return CategoryLockingContention
+	case "SERVER_UNAVAILABLE", "TRANSACTION_TIMEOUT", "UNKNOWN_ERROR":
+		return CategoryInternalError
+	case "REQUEST_EXCEEDED_LIMIT", "REQUEST_EXCEEDED_RATE":
+		return CategoryRequestExceeded
+	case "MALFORMED_QUERY", "INVALID_QUERY_LOCATOR":
+		return CategoryMalformedRequest
+	}
+
+	return CategoryUnknown
+}
 
-func (e errorResponse) Temporary() bool {
-	return isRetryableStatusCode(getStatus(e))
+// Error is returned when Zuora answers with an invalid HTTP status, or with a
+// `"success": false` body, which can happen even on a 200 response.
+// Use errors.As to inspect it and errors.Is to match it against sentinels such as ErrNotFound.
+type Error struct {
+	// StatusCode is the HTTP status returned by Zuora.
+	StatusCode int
+	// ProcessID is the Zuora processId, useful when opening a ticket with Zuora.
+	ProcessID string
+	// Reasons holds every reason returned by Zuora.
+	Reasons []Reason
+	// Errors holds the errors returned by object and action endpoints.
+	Errors []ObjectError
+	// Method and URL identify the request that failed.
+	Method string
+	URL    string
+	// Body is the raw response body.
+	Body string
+
+	message    string
+	retryAfter time.Duration
 }
 
-func (e errorResponse) Error() string {
+func (e *Error) Error() string {
+	if len(e.Reasons) == 0 && len(e.Errors) > 0 {
+		allMessages := []string{}
+		for i := 0; i < len(e.Errors); i++ {
+			allMessages = append(allMessages, fmt.Sprintf("error %v. Code: %v. Message: %v", i, e.Errors[i].Code, e.Errors[i].Message))
+		}
+
+		return fmt.Sprintf("all errors: %v", strings.Join(allMessages, " -- "))
+	}
+
 	if len(e.Reasons) == 0 {
+		if e.message != "" {
+			return e.message
+		}
+
 		return "there was an error on Zuora response but reasons array was empty."
 	}
 
@@ -97,16 +196,191 @@ func (e errorResponse) Error() string {
 	return fmt.Sprintf("all errors from reasons: %v", strings.Join(allMessages, " -- "))
 }
 
-func getStatus(e errorResponse) int {
-	if len(e.Reasons) == 0 {
-		return http.StatusBadRequest
+// Is reports whether e belongs to the category of one of the sentinel errors.
+func (e *Error) Is(target error) bool {
+	category, ok := sentinelCategories[target]
+
+	if !ok {
+		return false
 	}
 
-	allCodes := []int{}
-	for i := 0; i < len(e.Reasons); i++ {
-		currentCode := e.Reasons[i].Code
-		category := currentCode % 100
-		allCodes = append(allCodes, category)
+	return e.HasCategory(category) || statusCategory(e.StatusCode) == category
+}
+
+// HasCategory reports whether any of the reasons or errors belongs to category.
+func (e *Error) HasCategory(category ReasonCategory) bool {
+	for _, reason := range e.Reasons {
+		if reason.Category() == category {
+			return true
+		}
+	}
+
+	for _, objectError := range e.Errors {
+		if objectError.Category() == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Temporary reports whether the request can be retried.
+func (e *Error) Temporary() bool {
+	if len(e.Reasons) > 0 || len(e.Errors) > 0 {
+		return isRetryableStatusCode(getStatus(e.categories()))
+	}
+
+	return isRetryableStatusCode(e.StatusCode)
+}
+
+func (e *Error) categories() []ReasonCategory {
+	categories := []ReasonCategory{}
+
+	for _, reason := range e.Reasons {
+		categories = append(categories, reason.Category())
+	}
+
+	for _, objectError := range e.Errors {
+		categories = append(categories, objectError.Category())
+	}
+
+	return categories
+}
+
+// RetryAfter is the delay requested by Zuora through the Retry-After header, if any.
+func (e *Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// newError builds an *Error for a response, filling processId and reasons when the body carries them.
+func newError(req *http.Request, statusCode int, body []byte, message string) *Error {
+	e := &Error{
+		StatusCode: statusCode,
+		Body:       string(body),
+		message:    message,
+	}
+
+	if req != nil {
+		e.Method = req.Method
+		e.URL = req.URL.String()
+	}
+
+	errorResponse := errorResponse{}
+
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
+		e.ProcessID = errorResponse.ProcessID
+		e.Reasons = errorResponse.Reasons
+		e.Errors = errorResponse.Errors
+
+		if errorResponse.FaultCode != "" {
+			e.Errors = append(e.Errors, ObjectError{Code: errorResponse.FaultCode, Message: errorResponse.FaultString})
+		}
+	}
+
+	return e
+}
+
+// JobError is returned when an asynchronous job, such as an export, an AQuA job or a Data Query
+// job, fails or is cancelled, or when Zuora refuses it with an error code. Use errors.As to inspect it.
+type JobError struct {
+	// Job is the kind of job, such as export.
+	Job string
+	// ID and Status identify the job and the status it ended with.
+	ID     string
+	Status string
+	// Code is the error code returned by Zuora, if any.
+	Code    string
+	Message string
+}
+
+func (e *JobError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%v error %v: %v", e.Job, e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("%v %v is %v: %v", e.Job, e.ID, e.Status, e.Message)
+}
+
+// responseError is returned for failures that happen on the client side, before
+// Zuora answers: building or sending the request, or retrieving auth headers.
+type responseError struct {
+	isTemporary bool
+	message     string
+	err         error
+}
+
+func (r responseError) Temporary() bool {
+	return r.isTemporary
+}
+
+func (r responseError) Error() string {
+	return r.message
+}
+
+func (r responseError) Unwrap() error {
+	return r.err
+}
+
+// RetryAfter is the delay requested by the error responseError wraps, if any.
+func (r responseError) RetryAfter() time.Duration {
+	if retryAfter, ok := r.err.(interface{ RetryAfter() time.Duration }); ok {
+		return retryAfter.RetryAfter()
+	}
+
+	return 0
+}
+
+// temporary reports whether err is worth retrying, as told by its Temporary method.
+func temporary(err error) bool {
+	t, ok := err.(interface{ Temporary() bool })
+	return ok && t.Temporary()
+}
+
+func isRetryableStatusCode(statusCode int) bool {
+	return http.StatusRequestTimeout == statusCode ||
+		http.StatusTooManyRequests == statusCode ||
+		http.StatusInternalServerError == statusCode ||
+		http.StatusServiceUnavailable == statusCode
+}
+
+//errorResponse could happen even when you get a 200 response from Zuora.
+//The correct way to parse it, is to look at the Code inside reasons acording to Zuora docs.
+//Object and action endpoints use Errors, or faultcode and faultstring, instead of reasons.
+type errorResponse struct {
+	Success     *bool         `json:"success"`
+	ProcessID   string        `json:"processId"`
+	Reasons     []Reason      `json:"reasons"`
+	Errors      []ObjectError `json:"Errors"`
+	FaultCode   string        `json:"faultcode"`
+	FaultString string        `json:"faultstring"`
+}
+
+func (e errorResponse) failed() bool {
+	return (e.Success != nil && !*e.Success) || e.FaultCode != ""
+}
+
+func statusCategory(statusCode int) ReasonCategory {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return CategoryAuthFailed
+	case http.StatusForbidden:
+		return CategoryAccessDenied
+	case http.StatusNotFound:
+		return CategoryNotFound
+	case http.StatusLocked, http.StatusConflict:
+		return CategoryLockingContention
+	case http.StatusTooManyRequests:
+		return CategoryRequestExceeded
+	case http.StatusInternalServerError:
+		return CategoryInternalError
+	}
+
+	return CategoryUnknown
+}
+
+func getStatus(categories []ReasonCategory) int {
+	if len(categories) == 0 {
+		return http.StatusBadRequest
 	}
 
 	var isRequestExceeded bool
@@ -116,20 +390,19 @@ func getStatus(e errorResponse) int {
 	var isStatusLocked bool
 	var isInternalServerError bool
 
-	for i := 0; i < len(allCodes); i++ {
-		currentCode := allCodes[i]
-		switch currentCode {
-		case requestExceeded:
+	for i := 0; i < len(categories); i++ {
+		switch categories[i] {
+		case CategoryRequestExceeded:
 			isRequestExceeded = true
-		case accessDenied:
+		case CategoryAccessDenied:
 			isAccessDenied = true
-		case authFailed:
+		case CategoryAuthFailed:
 			isAuthDenied = true
-		case notFound:
+		case CategoryNotFound:
 			isNotFound = true
-		case lockingContention:
+		case CategoryLockingContention:
 			isStatusLocked = true
-		case internalError:
+		case CategoryInternalError:
 			isInternalServerError = true
 		}
 	}
@@ -150,19 +423,3 @@ func getStatus(e errorResponse) int {
 
 	return http.StatusBadRequest
 }
-
-type tokenError struct {
-	Timestamp string `json:"timestamp"`
-	Status    int    `json:"status"`
-	Message   string `json:"message"`
-	Path      string `json:"path"`
-	Reason    string `json:"reason"`
-}
-
-func (r tokenError) Temporary() bool {
-	return false
-}
-
-func (r tokenError) Error() string {
-	return fmt.Sprintf("could not generate token: %v - %v - %v - %v", r.Path, r.Reason, r.Status, r.Message)
-}
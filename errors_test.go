@@ -0,0 +1,127 @@
+package zuora
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReasonCodeParts(t *testing.T) {
+	reason := Reason{Code: 53100340}
+
+	if got := reason.ObjectCode(); got != 531 {
+		t.Errorf("Reason.ObjectCode() = %v, want 531", got)
+	}
+
+	if got := reason.FieldCode(); got != 3 {
+		t.Errorf("Reason.FieldCode() = %v, want 3", got)
+	}
+
+	if got := reason.Category(); got != CategoryNotFound {
+		t.Errorf("Reason.Category() = %v, want %v", got, CategoryNotFound)
+	}
+}
+
+func TestErrorFromSuccessFalseBody(t *testing.T) {
+	ctx := context.Background()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": false, "processId": "ABC123", "reasons": [{"code": 50000040, "message": "Cannot find entity by key: 'A0001'."}]}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	_, err := api.V1.AccountsService.Get(ctx, "A0001")
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("AccountsService.Get() = %v, want ErrNotFound", err)
+	}
+
+	if errors.Is(err, ErrLocked) {
+		t.Errorf("AccountsService.Get() = %v, should not match ErrLocked", err)
+	}
+
+	var zuoraErr *Error
+	if !errors.As(err, &zuoraErr) {
+		t.Fatalf("AccountsService.Get() = %T, want *Error", err)
+	}
+
+	if zuoraErr.ProcessID != "ABC123" || zuoraErr.Method != http.MethodGet || zuoraErr.URL != mockServer.URL+"/v1/accounts/A0001" {
+		t.Errorf("AccountsService.Get() = %+v, missing request details", zuoraErr)
+	}
+}
+
+func TestErrorFromHTTPStatus(t *testing.T) {
+	ctx := context.Background()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTooManyRequests)
+		rw.Write([]byte(`{"message": "You have exceeded your request limit"}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	_, err := api.V1.SubscriptionsService.ByKey(ctx, "A-S0001")
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("SubscriptionsService.ByKey() = %v, want ErrRateLimited", err)
+	}
+
+	var zuoraErr *Error
+	if !errors.As(err, &zuoraErr) || zuoraErr.StatusCode != http.StatusTooManyRequests || !zuoraErr.Temporary() {
+		t.Errorf("SubscriptionsService.ByKey() = %v, want a temporary *Error with status 429", err)
+	}
+}
+
+func TestErrorFromTokenEndpoint(t *testing.T) {
+	ctx := context.Background()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+		rw.Write([]byte(`{"message": "Bad credentials"}`))
+	}))
+	defer mockServer.Close()
+
+	oauthHeader := NewOAuthHeader(mockServer.Client(), &MemoryTokenStore{}, "testClientID", "testClientSecret", mockServer.URL)
+	api := newTestAPI(mockServer, WithAuthHeaderProvider(oauthHeader))
+	_, err := api.V1.AccountsService.Summary(ctx, "A0001")
+
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("AccountsService.Summary() = %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestErrorFromObjectEndpoints(t *testing.T) {
+	ctx := context.Background()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+		switch req.URL.Path {
+		case "/v1/object/invoice/missing":
+			rw.Write([]byte(`{"Success": false, "Errors": [{"Code": "INVALID_ID", "Message": "invalid id"}]}`))
+		case "/v1/action/query":
+			rw.Write([]byte(`{"faultcode": "fns:MALFORMED_QUERY", "faultstring": "You have an error in your ZOQL syntax"}`))
+		case "/v1/action/create":
+			rw.Write([]byte(`[{"Success": true, "Id": "1"}, {"Success": false, "Errors": [{"Code": "MISSING_REQUIRED_VALUE", "Message": "Name is required"}]}]`))
+		}
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+
+	if _, err := api.V1.Invoices.GetInvoice(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Invoices.GetInvoice() = %v, want ErrNotFound", err)
+	}
+
+	if _, err := api.V1.ActionsService.Query(ctx, ZOQL("select id form account")); !errors.Is(err, ErrMalformedRequest) {
+		t.Errorf("ActionsService.Query() = %v, want ErrMalformedRequest", err)
+	}
+
+	body, err := api.V1.ActionsService.Create(ctx, map[string]interface{}{"type": "Account"}, false)
+	if !errors.Is(err, ErrRequiredField) {
+		t.Errorf("ActionsService.Create() = %v, want ErrRequiredField", err)
+	}
+
+	if len(body) == 0 {
+		t.Errorf("ActionsService.Create() should return the raw results along with the error")
+	}
+}
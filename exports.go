@@ -0,0 +1,113 @@
+package zuora
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type exportsService struct {
+	client *client
+}
+
+func newExportsService(client *client) *exportsService {
+	return &exportsService{
+		client: client,
+	}
+}
+
+// Create starts a data source export and returns its ID. Exports are the supported way to pull
+// large datasets, including objects joined to related ones such as InvoiceItem to Account.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/Object_POSTExport
+func (t *exportsService) Create(ctx context.Context, exportRequest ExportRequest) (string, error) {
+	query, err := buildQuery(exportRequest.Query)
+
+	if err != nil {
+		return "", err
+	}
+
+	payload := exportCreatePayload{
+		Format: exportRequest.Format,
+		Name:   exportRequest.Name,
+		Query:  query,
+		Zip:    exportRequest.Zip,
+	}
+
+	if payload.Format == "" {
+		payload.Format = ExportFormatCSV
+	}
+
+	jsonResponse := exportCreateResponse{}
+
+	if err := t.client.doJSON(ctx, request{method: http.MethodPost, path: "/v1/object/export", body: payload}, &jsonResponse); err != nil {
+		return "", err
+	}
+
+	return jsonResponse.ID, nil
+}
+
+// Get returns the export exportID, including its status.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/Object_GETExport
+func (t *exportsService) Get(ctx context.Context, exportID string) (Export, error) {
+	path := fmt.Sprintf("/v1/object/export/%v", exportID)
+
+	jsonResponse := Export{}
+
+	if err := t.client.doJSON(ctx, request{method: http.MethodGet, path: path}, &jsonResponse); err != nil {
+		return Export{}, err
+	}
+
+	return jsonResponse, nil
+}
+
+// Wait polls the export exportID, waiting longer between every poll as set by WithPollInterval,
+// until it is completed. A cancelled or failed export returns an error along with the export.
+func (t *exportsService) Wait(ctx context.Context, exportID string) (Export, error) {
+	var export Export
+
+	err := t.client.poll(ctx, func() (bool, error) {
+		var err error
+
+		if export, err = t.Get(ctx, exportID); err != nil {
+			return false, err
+		}
+
+		switch export.Status {
+		case ExportStatusCompleted:
+			return true, nil
+		case ExportStatusCancelled, ExportStatusFailed:
+			return false, &JobError{Job: "export", ID: exportID, Status: string(export.Status), Message: export.StatusReason}
+		}
+
+		return false, nil
+	})
+
+	return export, err
+}
+
+// Download streams the file fileID, such as the FileID of a completed export.
+// The caller must close the returned reader.
+// More info at: https://www.zuora.com/developer/api-reference/#operation/GET_Files
+func (t *exportsService) Download(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/v1/files/%v", fileID)
+	return t.client.stream(ctx, request{method: http.MethodGet, path: path})
+}
+
+// Run creates the export, waits for its completion and streams its file.
+// Read CSV files with NewCSVDecoder. The caller must close the returned reader.
+func (t *exportsService) Run(ctx context.Context, exportRequest ExportRequest) (io.ReadCloser, error) {
+	exportID, err := t.Create(ctx, exportRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := t.Wait(ctx, exportID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Download(ctx, export.FileID)
+}
@@ -0,0 +1,121 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExportsServiceRun(t *testing.T) {
+	polls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/object/export":
+			payload := exportCreatePayload{}
+			json.NewDecoder(req.Body).Decode(&payload)
+
+			if want := "select Account.Name, InvoiceItem.Id, InvoiceItem.ChargeAmount from InvoiceItem"; payload.Query != want || payload.Format != ExportFormatCSV {
+				t.Errorf("export payload = %+v, want query %q in csv", payload, want)
+			}
+
+			rw.Write([]byte(`{"Success": true, "Id": "export1"}`))
+		case "/v1/object/export/export1":
+			polls++
+
+			if polls < 3 {
+				rw.Write([]byte(`{"Id": "export1", "Status": "Processing"}`))
+				return
+			}
+
+			rw.Write([]byte(`{"Id": "export1", "Status": "Completed", "FileId": "file1"}`))
+		case "/v1/files/file1":
+			rw.Write([]byte("Account.Name,InvoiceItem.Id,InvoiceItem.ChargeAmount\n\"Acme, Inc\",item1,10.5\nGlobex,item2,\n"))
+		default:
+			t.Errorf("unexpected request to %v", req.URL)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithPollInterval(time.Millisecond, 5*time.Millisecond))
+	file, err := api.V1.ExportsService.Run(context.Background(), ExportRequest{
+		Name:  "invoice items",
+		Query: Select("Account.Name", "InvoiceItem.Id", "InvoiceItem.ChargeAmount").From("InvoiceItem"),
+	})
+
+	if err != nil {
+		t.Fatalf("ExportsService.Run() returned an error: %v", err)
+	}
+	defer file.Close()
+
+	items := []struct {
+		AccountName  string   `zoql:"Account.Name"`
+		ID           string   `json:"Id"`
+		ChargeAmount *float64 `json:"ChargeAmount"`
+	}{}
+
+	if err := NewCSVDecoder(file).DecodeAll(&items); err != nil {
+		t.Fatalf("CSVDecoder.DecodeAll() returned an error: %v", err)
+	}
+
+	if len(items) != 2 || items[0].AccountName != "Acme, Inc" || items[0].ID != "item1" || *items[0].ChargeAmount != 10.5 || items[1].ChargeAmount != nil {
+		t.Errorf("CSVDecoder.DecodeAll() = %+v", items)
+	}
+
+	if polls != 3 {
+		t.Errorf("ExportsService.Run() polled %v times, want 3", polls)
+	}
+}
+
+func TestExportsServiceWaitFailed(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"Id": "export1", "Status": "Failed", "StatusReason": "invalid query"}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithPollInterval(time.Millisecond, 5*time.Millisecond))
+	export, err := api.V1.ExportsService.Wait(context.Background(), "export1")
+
+	jobErr := &JobError{}
+
+	if !errors.As(err, &jobErr) || jobErr.Status != "Failed" || !strings.Contains(err.Error(), "invalid query") || export.Status != ExportStatusFailed {
+		t.Errorf("ExportsService.Wait() = %+v, %v, want a failed export and a *JobError", export, err)
+	}
+}
+
+func TestExportsServiceWaitPollInterval(t *testing.T) {
+	var polls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		rw.Write([]byte(`{"Id": "export1", "Status": "Processing"}`))
+	}))
+	defer mockServer.Close()
+
+	for _, option := range []ConfigOption{WithPollInterval(10*time.Millisecond, 0), WithPollInterval(20*time.Millisecond, time.Millisecond)} {
+		atomic.StoreInt32(&polls, 0)
+		api := newTestAPI(mockServer, option)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		_, err := api.V1.ExportsService.Wait(ctx, "export1")
+		cancel()
+
+		if err == nil || atomic.LoadInt32(&polls) > 20 {
+			t.Errorf("ExportsService.Wait() polled %v times in 300ms with error %v, want a few polls and a timeout", atomic.LoadInt32(&polls), err)
+		}
+	}
+}
+
+func TestCSVDecoderMaps(t *testing.T) {
+	rows := NewCSVDecoder(strings.NewReader("Id,Name\n1,Acme\n"))
+	records := []map[string]string{}
+
+	if err := rows.DecodeAll(&records); err != nil || len(records) != 1 || records[0]["Name"] != "Acme" {
+		t.Errorf("CSVDecoder.DecodeAll() = %v, %v", records, err)
+	}
+}
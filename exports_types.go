@@ -0,0 +1,64 @@
+package zuora
+
+//ExportFormat is the format of the file produced by an export.
+type ExportFormat string
+
+//ExportFormatCSV comma separated values, the default format.
+const ExportFormatCSV ExportFormat = "csv"
+
+//ExportFormatTSV tab separated values.
+const ExportFormatTSV ExportFormat = "tsv"
+
+//ExportStatus is the processing status of an export.
+type ExportStatus string
+
+//Export statuses, an export is over once it is Completed, Cancelled or Failed.
+const (
+	ExportStatusPending    ExportStatus = "Pending"
+	ExportStatusProcessing ExportStatus = "Processing"
+	ExportStatusCompleted  ExportStatus = "Completed"
+	ExportStatusCancelled  ExportStatus = "Cancelled"
+	ExportStatusFailed     ExportStatus = "Failed"
+)
+
+//ExportRequest describes the data source export to create.
+type ExportRequest struct {
+	// Required. Name of the export.
+	Name string
+	// Required. Export ZOQL query, which can join related objects, for example
+	// Select("Account.Name", "InvoiceItem.ChargeAmount").From("InvoiceItem").
+	Query Querier
+	// Format of the file, defaults to ExportFormatCSV.
+	Format ExportFormat
+	// Zip compresses the file. Zipped files cannot be read with a CSVDecoder.
+	Zip bool
+}
+
+type exportCreatePayload struct {
+	Format ExportFormat `json:"Format"`
+	Name   string       `json:"Name"`
+	Query  string       `json:"Query"`
+	Zip    bool         `json:"Zip"`
+}
+
+type exportCreateResponse struct {
+	ID string `json:"Id"`
+}
+
+//Export is a data source export.
+//More info at: https://www.zuora.com/developer/api-reference/#tag/Exports
+type Export struct {
+	ID           string       `json:"Id"`
+	Name         string       `json:"Name"`
+	Query        string       `json:"Query"`
+	Format       ExportFormat `json:"Format"`
+	Zip          bool         `json:"Zip"`
+	Status       ExportStatus `json:"Status"`
+	StatusReason string       `json:"StatusReason"`
+	FileID       string       `json:"FileId"`
+	Size         int          `json:"Size"`
+	CreatedByID  string       `json:"CreatedById"`
+	CreatedDate  string       `json:"CreatedDate"`
+	UpdatedByID  string       `json:"UpdatedById"`
+	UpdatedDate  string       `json:"UpdatedDate"`
+}
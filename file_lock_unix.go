@@ -0,0 +1,41 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package zuora
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockFile takes an exclusive flock on path. The lock is released by the operating system
+// if the process dies while holding it.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+
+		if err == nil {
+			return func() {
+				syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+				file.Close()
+			}, nil
+		}
+
+		if err != syscall.EWOULDBLOCK {
+			file.Close()
+			return nil, err
+		}
+
+		if err := sleep(ctx, 50*time.Millisecond); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+}
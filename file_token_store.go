@@ -0,0 +1,142 @@
+package zuora
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileTokenStore keeps the OAuth token in a file so short-lived processes, such as CLIs
+// and cron jobs, reuse the same token instead of each creating a new one.
+// Processes sharing the file coordinate through a lock file next to it.
+// When created with a key, the token is encrypted at rest with AES-GCM.
+type FileTokenStore struct {
+	path string
+	aead cipher.AEAD
+}
+
+// NewFileTokenStore creates a FileTokenStore saving the token at path. key is optional,
+// when not nil it must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	store := &FileTokenStore{path: path}
+
+	if key == nil {
+		return store, nil
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid token encryption key: %v", err)
+	}
+
+	if store.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, fmt.Errorf("invalid token encryption key: %v", err)
+	}
+
+	return store, nil
+}
+
+// Token reads the token from the file. A missing file or an expired token returns a nil token.
+func (f *FileTokenStore) Token(ctx context.Context) (*Token, error) {
+	data, err := ioutil.ReadFile(f.path)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if f.aead != nil {
+		if data, err = f.open(data); err != nil {
+			return nil, err
+		}
+	}
+
+	token := &Token{}
+
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("error while Unmarshal token file %v: %v", f.path, err)
+	}
+
+	if !token.Expiry.IsZero() && time.Now().After(token.Expiry) {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// Update writes token to the file. The file is replaced atomically so readers never see a partial token.
+func (f *FileTokenStore) Update(ctx context.Context, token *Token) error {
+	data, err := json.Marshal(token)
+
+	if err != nil {
+		return err
+	}
+
+	if f.aead != nil {
+		if data, err = f.seal(data); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// Lock takes the lock shared by every process using the same file, waiting until it is free or ctx is done.
+func (f *FileTokenStore) Lock(ctx context.Context) (func(), error) {
+	return lockFile(ctx, f.path+".lock")
+}
+
+func (f *FileTokenStore) seal(data []byte) ([]byte, error) {
+	nonce := make([]byte, f.aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return f.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+func (f *FileTokenStore) open(data []byte) ([]byte, error) {
+	if len(data) < f.aead.NonceSize() {
+		return nil, errors.New("encrypted token file is too short")
+	}
+
+	nonce, ciphertext := data[:f.aead.NonceSize()], data[f.aead.NonceSize():]
+	plaintext, err := f.aead.Open(nil, nonce, ciphertext, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("error while decrypting token file %v: %v", f.path, err)
+	}
+
+	return plaintext, nil
+}
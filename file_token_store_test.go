@@ -0,0 +1,96 @@
+package zuora
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStoreEncryption(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "zuora")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	store, err := NewFileTokenStore(path, []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewFileTokenStore() returned an error: %v", err)
+	}
+
+	if token, err := store.Token(ctx); token != nil || err != nil {
+		t.Errorf("FileTokenStore.Token() = %v, %v, want nil, nil", token, err)
+	}
+
+	want := &Token{AccessToken: "reallylongaccesstoken", ExpiresIn: 3600, Expiry: time.Now().Add(time.Hour)}
+	if err := store.Update(ctx, want); err != nil {
+		t.Fatalf("FileTokenStore.Update() returned an error: %v", err)
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(data), want.AccessToken) {
+		t.Errorf("FileTokenStore.Update() wrote the token in clear text: %s", data)
+	}
+
+	got, err := store.Token(ctx)
+	if err != nil || got == nil || got.AccessToken != want.AccessToken {
+		t.Errorf("FileTokenStore.Token() = %v, %v, want %v", got, err, want)
+	}
+
+	other, _ := NewFileTokenStore(path, []byte("fedcba9876543210"))
+	if _, err := other.Token(ctx); err == nil {
+		t.Error("FileTokenStore.Token() with the wrong key wanted an error but got nil")
+	}
+}
+
+func TestFileTokenStoreSharedByProcesses(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "zuora")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"access_token": "reallylongaccesstoken", "token_type": "fake", "expires_in": 3600, "scope": "all", "jti": ""}`))
+	}))
+	defer mockServer.Close()
+
+	//Every OAuthHeader has its own store, as separate processes would
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		store, err := NewFileTokenStore(filepath.Join(dir, "token"), nil)
+		if err != nil {
+			t.Fatalf("NewFileTokenStore() returned an error: %v", err)
+		}
+
+		oauthHeader := NewOAuthHeader(mockServer.Client(), nil, "testClientID", "testClientSecret", mockServer.URL, OAuthTokenStore(store))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := oauthHeader.AuthHeaders(ctx); err != nil {
+				t.Errorf("OAuthHeader.AuthHeaders() returned an error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("OAuthHeader.AuthHeaders() requested %v tokens, want 1", calls)
+	}
+}
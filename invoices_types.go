@@ -55,7 +55,7 @@ type InvoiceItem struct {
 }
 
 //InvoiceItemZoql this includes all the fields that can be retrieved when
-// calling InvoiceItem through ZOQL query.
+// calling InvoiceItem through ZOQL query. Use it with SelectFor and QueryInto.
 type InvoiceItemZoql struct {
 	AccountingCode         string  `json:"AccountingCode,omitempty"`
 	AppliedToChargeNumber  string  `json:"AppliedToChargeNumber,omitempty"`
@@ -0,0 +1,212 @@
+package zuora
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LimiterConfig describes the limits a Limiter enforces. Zero values disable the matching limit.
+// More info about Zuora limits at:
+// https://knowledgecenter.zuora.com/Central_Platform/API/AB_Zuora_API_Rate_and_Concurrency_Limits
+type LimiterConfig struct {
+	// MaxConcurrent is the maximum number of requests in flight at the same time.
+	MaxConcurrent int
+	// RequestsPerMinute is the sustained rate of the token bucket.
+	RequestsPerMinute int
+	// Burst is the number of requests that can be sent at once when the bucket is full.
+	// Defaults to 1.
+	Burst int
+}
+
+// Limiter throttles requests going out to Zuora so they stay under the tenant
+// concurrency and rate limits. It also reads the rate limit headers sent back by Zuora
+// and holds every request when the remaining quota reaches zero.
+// A Limiter is safe for concurrent use and is meant to be shared by all services of an API.
+type Limiter struct {
+	slots chan struct{}
+
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewLimiter creates a Limiter from config.
+func NewLimiter(config LimiterConfig) *Limiter {
+	l := &Limiter{}
+
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+
+	if config.RequestsPerMinute > 0 {
+		l.rate = float64(config.RequestsPerMinute) / 60
+		l.burst = float64(config.Burst)
+
+		if l.burst < 1 {
+			l.burst = 1
+		}
+
+		l.tokens = l.burst
+		l.last = time.Now()
+	}
+
+	return l
+}
+
+// Wait blocks until a request can be sent or ctx is done. The returned function
+// must be called once the request is finished to free its concurrency slot.
+// The concurrency slot is taken first, so a request giving up while waiting for one
+// does not use up a rate token.
+func (l *Limiter) Wait(ctx context.Context) (func(), error) {
+	release := func() {}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		var once sync.Once
+		release = func() { once.Do(func() { <-l.slots }) }
+	}
+
+	if err := l.waitToken(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+func (l *Limiter) waitToken(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		var wait time.Duration
+
+		if now.Before(l.pausedUntil) {
+			wait = l.pausedUntil.Sub(now)
+		} else if l.rate > 0 {
+			l.tokens += now.Sub(l.last).Seconds() * l.rate
+			l.last = now
+
+			if l.tokens > l.burst {
+				l.tokens = l.burst
+			}
+
+			if l.tokens >= 1 {
+				l.tokens--
+			} else {
+				wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+			}
+		}
+		l.mu.Unlock()
+
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// observe adapts the limiter to the rate limit information returned by Zuora.
+func (l *Limiter) observe(res *http.Response) {
+	var pause time.Duration
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		pause = parseRetryAfter(res.Header.Get("Retry-After"))
+	}
+
+	remaining, ok := headerInt(res.Header, "X-RateLimit-Remaining-minute", "RateLimit-Remaining")
+	if ok && remaining <= 0 {
+		reset, ok := headerInt(res.Header, "RateLimit-Reset")
+		if !ok || reset <= 0 {
+			reset = 60
+		}
+
+		if d := time.Duration(reset) * time.Second; d > pause {
+			pause = d
+		}
+	}
+
+	if pause <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// headerInt returns the integer value of the first header present. Values such as
+// "100;w=60" are accepted and only the leading number is read.
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+
+		if i := strings.IndexAny(value, ";, "); i >= 0 {
+			value = value[:i]
+		}
+
+		n, err := strconv.Atoi(value)
+		if err == nil {
+			return n, true
+		}
+	}
+
+	return 0, false
+}
+
+func limiterMiddleware(l *Limiter) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			release, err := l.Wait(req.Context())
+
+			if err != nil {
+				return nil, responseError{isTemporary: false, message: fmt.Sprintf("error while waiting for the rate limiter: %v", err), err: err}
+			}
+
+			res, err := next.Do(req)
+
+			if err != nil {
+				release()
+				return nil, err
+			}
+
+			l.observe(res)
+			res.Body = &releaseBody{ReadCloser: res.Body, release: release}
+			return res, nil
+		})
+	}
+}
+
+// releaseBody frees the limiter slot once the response body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseBody) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
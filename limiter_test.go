@@ -0,0 +1,93 @@
+package zuora
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterMaxConcurrent(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithLimiter(NewLimiter(LimiterConfig{MaxConcurrent: 2})))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.V1.AccountsService.Summary(ctx, "A0001"); err != nil {
+				t.Errorf("AccountsService.Summary() returned an error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Limiter allowed %v requests in flight, want at most 2", peak)
+	}
+}
+
+func TestLimiterKeepsTokensOfCancelledWaits(t *testing.T) {
+	limiter := NewLimiter(LimiterConfig{MaxConcurrent: 1, RequestsPerMinute: 60, Burst: 2})
+
+	release, err := limiter.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Limiter.Wait() returned an error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.Wait(ctx); err == nil {
+		t.Errorf("Limiter.Wait() should fail while the only slot is taken")
+	}
+
+	release()
+
+	start := time.Now()
+	if _, err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Limiter.Wait() returned an error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Limiter.Wait() waited %v for a token, the cancelled wait should not have used it", elapsed)
+	}
+}
+
+func TestLimiterPausesWhenQuotaIsExhausted(t *testing.T) {
+	limiter := NewLimiter(LimiterConfig{})
+	res := &http.Response{StatusCode: 200, Header: http.Header{}}
+	res.Header.Set("RateLimit-Remaining", "0")
+	res.Header.Set("RateLimit-Reset", "30")
+	limiter.observe(res)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.Wait(ctx); err == nil {
+		t.Errorf("Limiter.Wait() wanted to block until the quota resets")
+	}
+}
@@ -32,5 +32,10 @@ func (m *MemoryTokenStore) Update(token *Token) {
 	m.Lock()
 	defer m.Unlock()
 	m.expiration = time.Now().UTC().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	if !token.Expiry.IsZero() {
+		m.expiration = token.Expiry.UTC()
+	}
+
 	m.token = token
 }
@@ -1,5 +1,7 @@
 package zuora
 
+import "reflect"
+
 //ObjecName represents name of zuora object
 type ObjecName string
 
@@ -199,3 +201,15 @@ func newObjectModel() ObjectModel {
 		Usage:                                    "Usage",
 	}
 }
+
+//Names returns the name of every object of the model, in the order they are declared
+func (o ObjectModel) Names() []ObjecName {
+	v := reflect.ValueOf(o)
+	names := make([]ObjecName, 0, v.NumField())
+
+	for i := 0; i < v.NumField(); i++ {
+		names = append(names, v.Field(i).Interface().(ObjecName))
+	}
+
+	return names
+}
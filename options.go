@@ -0,0 +1,209 @@
+package zuora
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultUserAgent is sent on every request unless WithUserAgent is used.
+const DefaultUserAgent = "hyeomans-zuora-go"
+
+// WithHTTPClient sets the client used to send requests. Defaults to http.DefaultClient.
+func WithHTTPClient(httpClient Doer) ConfigOption {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithBaseURL sends every request to baseURL, for example https://rest.apisandbox.zuora.com
+// It is a shortcut for WithEnvironment(CustomEnvironment(baseURL)).
+func WithBaseURL(baseURL string) ConfigOption {
+	return WithEnvironment(CustomEnvironment(baseURL))
+}
+
+// WithEnvironment sets the Zuora environment every service talks to, for example
+// EnvironmentEUSandbox or PCEEnvironment("https://pce.example.zuora.com").
+func WithEnvironment(environment Environment) ConfigOption {
+	return func(c *Config) {
+		c.Environment = environment
+		c.BaseURL = environment.BaseURL
+	}
+}
+
+// WithAuthHeaderProvider authenticates every request with authHeaderProvider.
+func WithAuthHeaderProvider(authHeaderProvider AuthHeaderProvider) ConfigOption {
+	return WithAuthProvider(AdaptAuthHeaderProvider(authHeaderProvider))
+}
+
+// WithAuthProvider authenticates every request with authProvider.
+func WithAuthProvider(authProvider AuthProvider) ConfigOption {
+	return func(c *Config) {
+		c.AuthProvider = authProvider
+	}
+}
+
+// WithOAuth authenticates with an OAuth token created from clientID and clientSecret.
+// Tokens are kept in a MemoryTokenStore unless WithTokenStore is used, options such as
+// OAuthExpirySkew customize how they are refreshed.
+func WithOAuth(clientID, clientSecret string, options ...OAuthOption) ConfigOption {
+	return func(c *Config) {
+		c.ClientID = clientID
+		c.ClientSecret = clientSecret
+		c.oauthOptions = options
+	}
+}
+
+// WithBasicAuth authenticates with basic HTTP auth, as required by the Production Copy Environment.
+func WithBasicAuth(username, password string) ConfigOption {
+	return func(c *Config) {
+		c.AuthProvider = NewBasicAuthHeader(username, password)
+	}
+}
+
+// WithAPIKey authenticates with the apiAccessKeyId and apiSecretAccessKey headers.
+func WithAPIKey(accessKeyID, secretAccessKey string) ConfigOption {
+	return func(c *Config) {
+		c.AuthProvider = NewAPIKeyHeader(accessKeyID, secretAccessKey)
+	}
+}
+
+// WithMultiOrgOAuth works like WithOAuth and also sends orgIDs in the Zuora-Org-Ids header,
+// as required by multi-org tenants.
+func WithMultiOrgOAuth(clientID, clientSecret string, orgIDs []string, options ...OAuthOption) ConfigOption {
+	return func(c *Config) {
+		WithOAuth(clientID, clientSecret, options...)(c)
+		c.orgIDs = orgIDs
+	}
+}
+
+// WithCredentialSource authenticates with an OAuth token created from the credentials provided
+// by credentials, which are picked up again whenever they are rotated. See WithOAuth for options.
+func WithCredentialSource(credentials CredentialSource, options ...OAuthOption) ConfigOption {
+	return func(c *Config) {
+		c.credentials = credentials
+		c.oauthOptions = options
+	}
+}
+
+// WithTokenStore sets where OAuth tokens created through WithOAuth are kept.
+func WithTokenStore(tokenStore TokenStorer) ConfigOption {
+	return func(c *Config) {
+		c.tokenStore = tokenStore
+	}
+}
+
+// WithRetryPolicy retries temporary failures, such as 429 or 503 responses, following policy.
+func WithRetryPolicy(policy RetryPolicy) ConfigOption {
+	return func(c *Config) {
+		c.RetryPolicy = &policy
+	}
+}
+
+// WithLimiter throttles every service with limiter so requests stay under the tenant
+// concurrency and rate limits. The same Limiter can be shared between several APIs
+// talking to the same tenant.
+func WithLimiter(limiter *Limiter) ConfigOption {
+	return func(c *Config) {
+		c.Limiter = limiter
+	}
+}
+
+// WithLogger logs every request and retry with logger.
+func WithLogger(logger Logger) ConfigOption {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithHeader adds a header to every request.
+func WithHeader(key, value string) ConfigOption {
+	return func(c *Config) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		}
+
+		c.Headers.Add(key, value)
+	}
+}
+
+// WithVersion sets the zuora-version header sent by default on every request.
+// CallVersion or ContextKeyZuoraVersion override it for a single call.
+func WithVersion(version string) ConfigOption {
+	return func(c *Config) {
+		c.Version = version
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent on every request.
+func WithUserAgent(userAgent string) ConfigOption {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithMiddleware adds middlewares to the chain every service goes through. Middlewares run after
+// authentication and header handling, so they see the final request sent to Zuora.
+func WithMiddleware(middlewares ...Middleware) ConfigOption {
+	return func(c *Config) {
+		c.Middlewares = append(c.Middlewares, middlewares...)
+	}
+}
+
+// WithPollInterval sets how often asynchronous jobs, such as exports, are polled. The interval
+// starts at interval and doubles after every poll up to max. Defaults to 1 and 30 seconds, which
+// zero or negative values keep, and max is raised to interval when it is lower.
+func WithPollInterval(interval, max time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.PollInterval = interval
+		c.MaxPollInterval = max
+	}
+}
+
+// WithActionConcurrency sets how many batches of objects actions such as CreateObjects send
+// at the same time. Defaults to 4.
+func WithActionConcurrency(concurrency int) ConfigOption {
+	return func(c *Config) {
+		c.ActionConcurrency = concurrency
+	}
+}
+
+// WithValidator checks the objects given to the create and update actions against describe
+// metadata with validator before sending them. Invalid objects are refused with a *ValidationError.
+func WithValidator(validator *Validator) ConfigOption {
+	return func(c *Config) {
+		c.Validator = validator
+	}
+}
+
+func newConfig(options ...ConfigOption) Config {
+	config := Config{
+		HTTPClient:        http.DefaultClient,
+		UserAgent:         DefaultUserAgent,
+		PollInterval:      time.Second,
+		MaxPollInterval:   30 * time.Second,
+		ActionConcurrency: 4,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	if config.credentials != nil {
+		config.oauthOptions = append([]OAuthOption{OAuthCredentials(config.credentials)}, config.oauthOptions...)
+	}
+
+	if config.AuthProvider == nil && (config.ClientID != "" || config.credentials != nil) {
+		if config.tokenStore == nil {
+			config.tokenStore = &MemoryTokenStore{}
+		}
+
+		oauthHeader := NewOAuthHeader(config.HTTPClient, config.tokenStore, config.ClientID, config.ClientSecret, config.BaseURL, config.oauthOptions...)
+		config.AuthProvider = oauthHeader
+
+		if len(config.orgIDs) > 0 {
+			config.AuthProvider = NewMultiOrgOAuthHeader(oauthHeader, config.orgIDs...)
+		}
+	}
+
+	return config
+}
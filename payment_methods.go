@@ -2,91 +2,29 @@ package zuora
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 )
 
 type paymentMethods struct {
-	http               Doer
-	authHeaderProvider AuthHeaderProvider
-	baseURL            string
-	isPce              bool
+	client *client
 }
 
-func newPaymentMethods(http Doer, authHeaderProvider AuthHeaderProvider, baseURL string, isPce bool) *paymentMethods {
+func newPaymentMethods(client *client) *paymentMethods {
 	return &paymentMethods{
-		http:               http,
-		authHeaderProvider: authHeaderProvider,
-		baseURL:            baseURL,
-		isPce:              isPce,
+		client: client,
 	}
 }
 
-//GetPaymentMethod Retrieves a specific Payment Method by ObjectID
+// GetPaymentMethod Retrieves a specific Payment Method by ObjectID
 // More info at: https://www.zuora.com/developer/api-reference/#operation/Object_GETPaymentMethod
 func (t *paymentMethods) GetPaymentMethod(ctx context.Context, objectID string) (PaymentMethod, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	var url string
-
-	if t.isPce {
-		url = fmt.Sprintf("%v:19016/v1/object/payment-method/%v", t.baseURL, objectID)
-	} else {
-		url = fmt.Sprintf("%v/v1/object/payment-method/%v", t.baseURL, objectID)
-	}
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-
-	if err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
-	}
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	res, err := t.http.Do(req.WithContext(ctx))
-
-	if err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return PaymentMethod{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
-
-		return PaymentMethod{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
-	}
+	path := fmt.Sprintf("/v1/object/payment-method/%v", objectID)
 
 	jsonResponse := PaymentMethod{}
 
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
+	if err := t.client.doJSON(ctx, request{method: http.MethodGet, path: path}, &jsonResponse); err != nil {
+		return PaymentMethod{}, err
 	}
 
 	return jsonResponse, nil
@@ -97,66 +35,12 @@ func (t *paymentMethods) GetPaymentMethod(ctx context.Context, objectID string)
 // data used in each of the past transactions.
 // More info at: https://www.zuora.com/developer/api-reference/#operation/Object_GETPaymentMethodSnapshot
 func (t *paymentMethods) GetPaymentMethodSnapshot(ctx context.Context, snapshotID string) (PaymentMethod, error) {
-	authHeader, err := t.authHeaderProvider.AuthHeaders(ctx)
-
-	if err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to set auth headers: %v", err)}
-	}
-
-	var url string
-
-	if t.isPce {
-		url = fmt.Sprintf("%v:19016/v1/object/payment-method-snapshot/%v", t.baseURL, snapshotID)
-	} else {
-		url = fmt.Sprintf("%v/v1/object/payment-method-snapshot/%v", t.baseURL, snapshotID)
-	}
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-
-	if err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to create an HTTP request: %v", err)}
-	}
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("Content-Type", "application/json")
-
-	if ctx.Value(ContextKeyZuoraEntityIds) != nil {
-		req.Header.Add("Zuora-Entity-Ids", ctx.Value(ContextKeyZuoraEntityIds).(string))
-	}
-
-	if ctx.Value(ContextKeyZuoraTrackID) != nil {
-		req.Header.Add("Zuora-Track-Id", ctx.Value(ContextKeyZuoraTrackID).(string))
-	}
-
-	res, err := t.http.Do(req.WithContext(ctx))
-
-	if err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while trying to make request: %v", err)}
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		var isTemporary bool
-		if http.StatusRequestTimeout == res.StatusCode ||
-			http.StatusTooManyRequests == res.StatusCode ||
-			http.StatusInternalServerError == res.StatusCode ||
-			http.StatusServiceUnavailable == res.StatusCode {
-			isTemporary = true
-		}
-
-		if err != nil {
-			return PaymentMethod{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("error while trying to read body response into memory. Response Code: %v - Error: %v", res.StatusCode, err)}
-		}
-
-		return PaymentMethod{}, responseError{isTemporary: isTemporary, message: fmt.Sprintf("got an invalid http status. Response Code: %v - Body: %v", res.StatusCode, string(body))}
-	}
+	path := fmt.Sprintf("/v1/object/payment-method-snapshot/%v", snapshotID)
 
 	jsonResponse := PaymentMethod{}
 
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return PaymentMethod{}, responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal json response. Error: %v. JSON: %v", err, string(body))}
+	if err := t.client.doJSON(ctx, request{method: http.MethodGet, path: path}, &jsonResponse); err != nil {
+		return PaymentMethod{}, err
 	}
 
 	return jsonResponse, nil
@@ -0,0 +1,123 @@
+package zuora
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries requests that failed with a temporary error,
+// for example a 429 or a 503 from Zuora.
+//
+// Only idempotent requests are retried: GET, HEAD, PUT, DELETE, OPTIONS, read-only endpoints
+// such as ZOQL queries, and any request carrying an idempotency key through ContextKeyIdempotencyKey or CallIdempotencyKey.
+// PUT endpoints with side effects, such as SubscriptionsService.Update and Cancel, need an idempotency key too.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles on every following attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential delay between attempts. A Retry-After header sent
+	// by Zuora is always honored, even when it is longer than MaxDelay.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns a policy that makes up to 4 attempts, starting with
+// a 500ms delay and never waiting more than 30 seconds between attempts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+// backoff returns the delay before the given retry attempt (1 being the first retry)
+// using exponential backoff with equal jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// next decides if a request that failed with err on the given attempt should be retried,
+// and how long to wait before doing so.
+func (p *RetryPolicy) next(ctx context.Context, r request, attempt int, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || !isIdempotent(ctx, r) {
+		return 0, false
+	}
+
+	if !temporary(err) {
+		return 0, false
+	}
+
+	if retryAfter, ok := err.(interface{ RetryAfter() time.Duration }); ok && retryAfter.RetryAfter() > 0 {
+		return retryAfter.RetryAfter(), true
+	}
+
+	return p.backoff(attempt), true
+}
+
+func isIdempotent(ctx context.Context, r request) bool {
+	if _, ok := idempotencyKey(ctx); r.idempotent || ok {
+		return true
+	}
+
+	if r.nonIdempotent {
+		return false
+	}
+
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header expressed either in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+// sleep waits for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,152 @@
+package zuora
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts < 3 {
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+
+	start := time.Now()
+	if _, err := api.V1.AccountsService.Get(ctx, "A0001"); err != nil {
+		t.Errorf("AccountsService.Get() returned an error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("AccountsService.Get() made %v attempts, want 3", attempts)
+	}
+
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		t.Errorf("AccountsService.Get() retried after %v, want Retry-After to be honored", elapsed)
+	}
+}
+
+func TestRetrySkipsNonIdempotentRequests(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		if req.Header.Get("Idempotency-Key") == "" || attempts < 2 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+
+	if _, err := api.V1.AccountsService.Create(ctx, map[string]string{"name": "test"}); err == nil {
+		t.Errorf("AccountsService.Create() wanted an error without an idempotency key")
+	}
+
+	if attempts != 1 {
+		t.Errorf("AccountsService.Create() made %v attempts, want 1", attempts)
+	}
+
+	attempts = 0
+	ctx = context.WithValue(ctx, ContextKeyIdempotencyKey, "create-A0001")
+	if _, err := api.V1.AccountsService.Create(ctx, map[string]string{"name": "test"}); err != nil {
+		t.Errorf("AccountsService.Create() returned an error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("AccountsService.Create() made %v attempts, want 2", attempts)
+	}
+}
+
+func TestRetryRetriesTokenRequests(t *testing.T) {
+	ctx := context.Background()
+	tokenAttempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/oauth/token" {
+			rw.WriteHeader(200)
+			rw.Write([]byte(`{"success": true}`))
+			return
+		}
+
+		tokenAttempts++
+		if tokenAttempts < 2 {
+			rw.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"access_token": "reallylongaccesstoken", "token_type": "bearer", "expires_in": 3600}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewAPI(
+		WithHTTPClient(mockServer.Client()),
+		WithOAuth("testClientID", "testClientSecret"),
+		WithBaseURL(mockServer.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+
+	if _, err := api.V1.AccountsService.Get(ctx, "A0001"); err != nil {
+		t.Errorf("AccountsService.Get() returned an error: %v", err)
+	}
+
+	if tokenAttempts != 2 {
+		t.Errorf("AccountsService.Get() requested a token %v times, want 2", tokenAttempts)
+	}
+}
+
+func TestRetrySkipsSubscriptionChanges(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts%2 == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	update := map[string]interface{}{"add": []map[string]string{{"productRatePlanId": "P1"}}}
+
+	if _, err := api.V1.SubscriptionsService.Update(ctx, "A-S0001", update); err == nil {
+		t.Errorf("SubscriptionsService.Update() wanted an error without an idempotency key")
+	}
+
+	attempts = 0
+	if _, err := api.V1.SubscriptionsService.Cancel(ctx, "A-S0001", SubscriptionCancellation{CancellationPolicy: "EndOfCurrentTerm"}); err == nil {
+		t.Errorf("SubscriptionsService.Cancel() wanted an error without an idempotency key")
+	}
+
+	if attempts != 1 {
+		t.Errorf("SubscriptionsService.Cancel() made %v attempts, want 1", attempts)
+	}
+
+	attempts = 0
+	ctx = context.WithValue(ctx, ContextKeyIdempotencyKey, "update-A-S0001")
+	if _, err := api.V1.SubscriptionsService.Update(ctx, "A-S0001", update); err != nil {
+		t.Errorf("SubscriptionsService.Update() returned an error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("SubscriptionsService.Update() made %v attempts, want 2", attempts)
+	}
+}
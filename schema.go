@@ -0,0 +1,208 @@
+package zuora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema holds the description of the objects of a tenant, keyed by object name.
+type Schema map[string]*ObjectDescription
+
+// NewSchema returns the schema made of descriptions, for example read from saved describe files.
+func NewSchema(descriptions ...*ObjectDescription) Schema {
+	schema := Schema{}
+
+	for _, description := range descriptions {
+		schema[description.Name] = description
+	}
+
+	return schema
+}
+
+// Schema describes objects, or every object of the ObjectModel when none is given.
+// Objects the tenant does not have, which Zuora answers with a 404, are left out.
+func (t *describeService) Schema(ctx context.Context, objects ...ObjecName) (Schema, error) {
+	if len(objects) == 0 {
+		objects = newObjectModel().Names()
+	}
+
+	schema := Schema{}
+
+	for _, object := range objects {
+		description, err := t.Object(ctx, object)
+
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		schema[description.Name] = description
+	}
+
+	return schema, nil
+}
+
+// Change is the kind of difference found between two schemas.
+type Change string
+
+const (
+	//ChangeAdded the object or field only exists in the second schema
+	ChangeAdded Change = "added"
+	//ChangeRemoved the object or field only exists in the first schema
+	ChangeRemoved Change = "removed"
+	//ChangeChanged the field exists in both schemas with different properties
+	ChangeChanged Change = "changed"
+)
+
+// SchemaDiff lists the differences between two schemas, see CompareSchemas.
+type SchemaDiff struct {
+	Objects []ObjectDiff `json:"objects"`
+	Fields  []FieldDiff  `json:"fields"`
+}
+
+// Empty reports whether both schemas are the same.
+func (d SchemaDiff) Empty() bool {
+	return len(d.Objects) == 0 && len(d.Fields) == 0
+}
+
+// ObjectDiff is an object found in only one of the schemas.
+type ObjectDiff struct {
+	Object string `json:"object"`
+	Change Change `json:"change"`
+}
+
+// FieldDiff is a field added, removed or changed between two schemas.
+type FieldDiff struct {
+	Object string `json:"object"`
+	Field  string `json:"field"`
+	Change Change `json:"change"`
+	// Properties lists what changed when Change is ChangeChanged.
+	Properties []PropertyDiff `json:"properties,omitempty"`
+}
+
+// PropertyDiff is a field property, such as type or maxlength, whose value differs between two schemas.
+type PropertyDiff struct {
+	Property string `json:"property"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+// CompareSchemas reports what differs in to compared to from, for example a production
+// tenant compared to its sandbox. Only objects present in both are compared field by field,
+// matching names without regard to case. The differences are sorted by object and field name.
+func CompareSchemas(from, to Schema) SchemaDiff {
+	diff := SchemaDiff{Objects: []ObjectDiff{}, Fields: []FieldDiff{}}
+
+	for _, name := range schemaNames(from, to) {
+		fromObject, inFrom := from[name]
+		toObject, inTo := to[name]
+
+		switch {
+		case !inFrom:
+			diff.Objects = append(diff.Objects, ObjectDiff{Object: name, Change: ChangeAdded})
+		case !inTo:
+			diff.Objects = append(diff.Objects, ObjectDiff{Object: name, Change: ChangeRemoved})
+		default:
+			diff.Fields = append(diff.Fields, compareFields(name, fromObject, toObject)...)
+		}
+	}
+
+	return diff
+}
+
+func compareFields(object string, from, to *ObjectDescription) []FieldDiff {
+	fromFields, toFields := fieldsByName(from), fieldsByName(to)
+	names := []string{}
+
+	for name := range fromFields {
+		names = append(names, name)
+	}
+
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	diffs := []FieldDiff{}
+
+	for _, name := range names {
+		fromField, inFrom := fromFields[name]
+		toField, inTo := toFields[name]
+
+		switch {
+		case !inFrom:
+			diffs = append(diffs, FieldDiff{Object: object, Field: toField.Name, Change: ChangeAdded})
+		case !inTo:
+			diffs = append(diffs, FieldDiff{Object: object, Field: fromField.Name, Change: ChangeRemoved})
+		default:
+			if properties := compareProperties(fromField, toField); len(properties) > 0 {
+				diffs = append(diffs, FieldDiff{Object: object, Field: toField.Name, Change: ChangeChanged, Properties: properties})
+			}
+		}
+	}
+
+	return diffs
+}
+
+func compareProperties(from, to FieldDescription) []PropertyDiff {
+	properties := []PropertyDiff{}
+
+	for _, property := range []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"type", from.Type, to.Type},
+		{"label", from.Label, to.Label},
+		{"custom", from.Custom, to.Custom},
+		{"selectable", from.Selectable, to.Selectable},
+		{"createable", from.Createable, to.Createable},
+		{"updateable", from.Updateable, to.Updateable},
+		{"filterable", from.Filterable, to.Filterable},
+		{"required", from.Required, to.Required},
+		{"maxlength", from.MaxLength, to.MaxLength},
+		{"options", strings.Join(from.Options, ","), strings.Join(to.Options, ",")},
+	} {
+		fromValue, toValue := fmt.Sprint(property.from), fmt.Sprint(property.to)
+
+		if fromValue != toValue {
+			properties = append(properties, PropertyDiff{Property: property.name, From: fromValue, To: toValue})
+		}
+	}
+
+	return properties
+}
+
+func fieldsByName(object *ObjectDescription) map[string]FieldDescription {
+	fields := map[string]FieldDescription{}
+
+	for _, field := range object.Fields {
+		fields[strings.ToLower(field.Name)] = field
+	}
+
+	return fields
+}
+
+func schemaNames(schemas ...Schema) []string {
+	seen := map[string]bool{}
+	names := []string{}
+
+	for _, schema := range schemas {
+		for name := range schema {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,73 @@
+package zuora
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeSchema(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/describe/Account":
+			rw.Write([]byte(accountDescription))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+
+	schema, err := api.V1.DescribeService.Schema(context.Background(), api.ObjectModel.Account, api.ObjectModel.OrderElp)
+	if err != nil || len(schema) != 1 || schema["Account"] == nil {
+		t.Errorf("DescribeService.Schema() = %v, %v, want only Account", schema, err)
+	}
+
+	names := api.ObjectModel.Names()
+	if len(names) != reflect.TypeOf(ObjectModel{}).NumField() || names[0] != "Account" {
+		t.Errorf("ObjectModel.Names() = %v", names)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	sandbox := NewSchema(
+		&ObjectDescription{Name: "Account", Fields: []FieldDescription{
+			{Name: "Name", Type: "text", MaxLength: 255, Required: true},
+			{Name: "Region__c", Type: "text", Custom: true, MaxLength: 50},
+			{Name: "Status", Type: "picklist", Options: []string{"Draft", "Active"}},
+		}},
+		&ObjectDescription{Name: "Invoice"},
+	)
+
+	production := NewSchema(
+		&ObjectDescription{Name: "Account", Fields: []FieldDescription{
+			{Name: "Name", Type: "text", MaxLength: 255, Required: true},
+			{Name: "status", Type: "picklist", Options: []string{"Draft", "Active", "Canceled"}},
+			{Name: "Tier__c", Type: "picklist", Custom: true},
+		}},
+		&ObjectDescription{Name: "Usage"},
+	)
+
+	diff := CompareSchemas(sandbox, production)
+
+	wantObjects := []ObjectDiff{{Object: "Invoice", Change: ChangeRemoved}, {Object: "Usage", Change: ChangeAdded}}
+	if !reflect.DeepEqual(diff.Objects, wantObjects) {
+		t.Errorf("CompareSchemas().Objects = %+v, want %+v", diff.Objects, wantObjects)
+	}
+
+	wantFields := []FieldDiff{
+		{Object: "Account", Field: "Region__c", Change: ChangeRemoved},
+		{Object: "Account", Field: "status", Change: ChangeChanged, Properties: []PropertyDiff{{Property: "options", From: "Draft,Active", To: "Draft,Active,Canceled"}}},
+		{Object: "Account", Field: "Tier__c", Change: ChangeAdded},
+	}
+	if !reflect.DeepEqual(diff.Fields, wantFields) {
+		t.Errorf("CompareSchemas().Fields = %+v, want %+v", diff.Fields, wantFields)
+	}
+
+	if diff.Empty() || !CompareSchemas(sandbox, sandbox).Empty() {
+		t.Errorf("SchemaDiff.Empty() should only report schemas without differences")
+	}
+}
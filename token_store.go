@@ -0,0 +1,45 @@
+package zuora
+
+import (
+	"context"
+)
+
+// TokenStore keeps OAuth tokens in a backing store, such as a file, a database or a cache,
+// shared by several clients. Unlike TokenStorer, it honors cancellation and reports failures.
+// Token returns a nil token when none is stored or the stored one has expired.
+type TokenStore interface {
+	Token(ctx context.Context) (*Token, error)
+	Update(ctx context.Context, token *Token) error
+}
+
+// TokenStoreLocker is implemented by a TokenStore shared by several processes. The lock is held
+// while a new token is fetched, so the other processes wait for it instead of fetching their own.
+type TokenStoreLocker interface {
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// AdaptTokenStorer turns a TokenStorer into a TokenStore.
+func AdaptTokenStorer(tokenStorer TokenStorer) TokenStore {
+	if tokenStorer == nil {
+		return nil
+	}
+
+	return tokenStorerAdapter{tokenStorer: tokenStorer}
+}
+
+type tokenStorerAdapter struct {
+	tokenStorer TokenStorer
+}
+
+func (a tokenStorerAdapter) Token(ctx context.Context) (*Token, error) {
+	if isValid, token := a.tokenStorer.Token(); isValid {
+		return token, nil
+	}
+
+	return nil, nil
+}
+
+func (a tokenStorerAdapter) Update(ctx context.Context, token *Token) error {
+	a.tokenStorer.Update(token)
+	return nil
+}
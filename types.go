@@ -2,6 +2,7 @@ package zuora
 
 import (
 	"net/http"
+	"time"
 )
 
 // Doer is a common interface for Http clients
@@ -13,16 +14,38 @@ type Doer interface {
 //ConfigOption helper function to modify current Config struct
 type ConfigOption func(*Config)
 
-//Config is the base configuration to return ZuoraApi
+//Config is the base configuration to return ZuoraApi. Use the With* options
+//to fill it when calling NewAPI.
 type Config struct {
-	HTTPClient   *http.Client
-	BaseURL      string
-	ClientID     string
-	ClientSecret string
-	tokenStore   TokenStorer
+	HTTPClient        Doer
+	BaseURL           string
+	Environment       Environment
+	ClientID          string
+	ClientSecret      string
+	AuthProvider      AuthProvider
+	RetryPolicy       *RetryPolicy
+	Limiter           *Limiter
+	Logger            Logger
+	Headers           http.Header
+	Version           string
+	UserAgent         string
+	Middlewares       []Middleware
+	PollInterval      time.Duration
+	MaxPollInterval   time.Duration
+	ActionConcurrency int
+	Validator         *Validator
+	tokenStore        TokenStorer
+	oauthOptions      []OAuthOption
+	orgIDs            []string
+	credentials       CredentialSource
 }
 
-//Querier One who, or that which, queries actions
+//Logger is satisfied by *log.Logger and most logging libraries.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+//Querier One who, or that which, queries actions. It is implemented by QueryBuilder and ZOQL.
 type Querier interface {
 	Build() string
 }
@@ -40,9 +63,13 @@ const ContextKeyZuoraEntityIds = ContextKey("Zuora-Entity-Ids")
 //ContextKeyZuoraTrackID will be addeed as a header on requests
 const ContextKeyZuoraTrackID = ContextKey("Zuora-Track-Id")
 
-//ContextKeyZuoraVersion will be added as header when an endpoint supports it.
+//ContextKeyZuoraVersion will be added as the zuora-version header on requests. See CallVersion for a typed alternative.
 const ContextKeyZuoraVersion = ContextKey("zuora-version")
 
+//ContextKeyIdempotencyKey will be added as the Idempotency-Key header on requests. Setting it
+//allows the client to retry non-idempotent requests such as POST.
+const ContextKeyIdempotencyKey = ContextKey("Idempotency-Key")
+
 //Response is a generic catch all struct to check if a response was successfull
 type Response struct {
 	Success bool `json:"success"`
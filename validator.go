@@ -0,0 +1,377 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ObjectDescriber returns the metadata of an object. It is implemented by DescribeService,
+// which asks the tenant, and by Schema, which holds metadata described or read earlier.
+type ObjectDescriber interface {
+	Object(ctx context.Context, objectName ObjecName) (*ObjectDescription, error)
+}
+
+// Object returns the description of objectName held by the schema.
+func (s Schema) Object(ctx context.Context, objectName ObjecName) (*ObjectDescription, error) {
+	for name, description := range s {
+		if strings.EqualFold(name, string(objectName)) {
+			return description, nil
+		}
+	}
+
+	return nil, fmt.Errorf("object %v is not described by the schema", objectName)
+}
+
+// Validator checks payloads against describe metadata before they are sent, so mistakes are
+// caught without a round trip to Zuora. Descriptions are cached for the lifetime given to NewValidator.
+//
+//	validator := zuora.NewValidator(zuoraAPI.V1.DescribeService, time.Hour)
+//
+//	if err := validator.ValidateUpdate(ctx, "Account", account); err != nil {
+//		return err // A *ValidationError listing every violation
+//	}
+//
+// Payloads are matched against the fields of the object without regard to case. Nested objects are
+// checked when they are related objects in the describe metadata, such as the BillToContact of an Account.
+//
+// Once given to WithValidator, it also checks the payloads of AccountsService.Update and
+// SubscriptionsService.Update. REST names do not always match the describe metadata, salesRep
+// is SalesRepName for instance, so keys matching no field are left to Zuora and only the fields
+// that match are checked. The add and update lists of a subscription are checked against RatePlan,
+// for text too long only, since the endpoint completes them before creating or updating the objects.
+type Validator struct {
+	describer ObjectDescriber
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedDescription
+}
+
+type cachedDescription struct {
+	description *ObjectDescription
+	expiry      time.Time
+}
+
+// NewValidator returns a Validator reading metadata from describer. Descriptions are described again
+// after ttl, zero keeps them forever.
+func NewValidator(describer ObjectDescriber, ttl time.Duration) *Validator {
+	return &Validator{describer: describer, ttl: ttl, cache: map[string]cachedDescription{}}
+}
+
+// ValidateCreate checks payload, an object or a slice of objects, before creating it as objectName:
+// every field must exist and be createable, required fields must be set, and text must fit in maxlength.
+func (v *Validator) ValidateCreate(ctx context.Context, objectName ObjecName, payload interface{}) error {
+	return v.validate(ctx, objectName, payload, checkCreate, false)
+}
+
+// ValidateUpdate checks payload, an object or a slice of objects, before updating objectName: every
+// field must exist and be updateable, and text must fit in maxlength. Id is accepted to identify the object.
+func (v *Validator) ValidateUpdate(ctx context.Context, objectName ObjecName, payload interface{}) error {
+	return v.validate(ctx, objectName, payload, checkUpdate, false)
+}
+
+// validateRESTUpdate checks payload before a REST endpoint updates objectName, see restObjects.
+func (v *Validator) validateRESTUpdate(ctx context.Context, objectName ObjecName, payload interface{}) error {
+	return v.validate(ctx, objectName, payload, checkUpdate, true)
+}
+
+func (v *Validator) validate(ctx context.Context, objectName ObjecName, payload interface{}, mode checkMode, rest bool) error {
+	data, err := json.Marshal(payload)
+
+	if err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Marshal payload to validate. Error: %v", err), err: err}
+	}
+
+	var value interface{}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal payload to validate. Error: %v", err), err: err}
+	}
+
+	check := &validation{validator: v, rest: rest}
+
+	if err := check.value(ctx, objectName, "", value, mode); err != nil {
+		return err
+	}
+
+	if len(check.violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Object: string(objectName), Violations: check.violations}
+}
+
+func (v *Validator) describe(ctx context.Context, objectName ObjecName) (*ObjectDescription, error) {
+	key := strings.ToLower(string(objectName))
+
+	v.mu.Lock()
+	cached, ok := v.cache[key]
+	v.mu.Unlock()
+
+	if ok && (cached.expiry.IsZero() || time.Now().Before(cached.expiry)) {
+		return cached.description, nil
+	}
+
+	description, err := v.describer.Object(ctx, objectName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cached = cachedDescription{description: description}
+	if v.ttl > 0 {
+		cached.expiry = time.Now().Add(v.ttl)
+	}
+
+	v.mu.Lock()
+	v.cache[key] = cached
+	v.mu.Unlock()
+
+	return description, nil
+}
+
+// checkMode is what a payload, or a part of it, is checked for.
+type checkMode int
+
+const (
+	checkCreate checkMode = iota
+	checkUpdate
+	// checkItem only looks for text too long.
+	checkItem
+)
+
+// restObjects lists, by lower case object name, the keys of REST payloads holding other objects,
+// and the object their items are checked against, such as the rate plans a subscription update adds.
+var restObjects = map[string]map[string]ObjecName{
+	"subscription": {
+		"add":    "RatePlan",
+		"update": "RatePlan",
+	},
+	"rateplan": {
+		"chargeoverrides":     "RatePlanCharge",
+		"chargeupdatedetails": "RatePlanCharge",
+	},
+	"rateplancharge": {
+		"tiers": "RatePlanChargeTier",
+	},
+}
+
+// validation collects the violations of a single payload.
+type validation struct {
+	validator  *Validator
+	rest       bool
+	violations []Violation
+}
+
+// value checks value, an object or a list of objects, against objectName.
+func (c *validation) value(ctx context.Context, objectName ObjecName, prefix string, value interface{}, mode checkMode) error {
+	switch v := value.(type) {
+	case []interface{}:
+		for i, item := range v {
+			if err := c.value(ctx, objectName, fmt.Sprintf("%v[%v]", prefix, i), item, mode); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		return c.object(ctx, objectName, prefix, v, mode)
+	default:
+		c.add(prefix, "", ViolationInvalidFormat, fmt.Sprintf("%v must be an object", describePath(prefix, string(objectName))))
+	}
+
+	return nil
+}
+
+func (c *validation) object(ctx context.Context, objectName ObjecName, prefix string, object map[string]interface{}, mode checkMode) error {
+	description, err := c.validator.describe(ctx, objectName)
+
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, fieldPath := object[key], joinPath(prefix, key)
+
+		if target, ok := restObjects[strings.ToLower(description.Name)][strings.ToLower(key)]; c.rest && ok {
+			if value != nil {
+				if err := c.value(ctx, target, fieldPath, value, checkItem); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if related, ok := relatedObject(description, key); ok {
+			if value != nil {
+				if err := c.value(ctx, related, fieldPath, value, mode); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if mode != checkCreate && strings.EqualFold(key, "Id") {
+			continue
+		}
+
+		field, ok := description.Field(key)
+
+		switch {
+		case !ok && c.rest:
+			continue
+		case !ok:
+			c.add(fieldPath, key, ViolationUnknownField, fmt.Sprintf("%v is not a field of %v", fieldPath, description.Name))
+			continue
+		case mode == checkUpdate && !field.Updateable:
+			c.add(fieldPath, key, ViolationNotUpdateable, fmt.Sprintf("%v cannot be updated", fieldPath))
+		case mode == checkCreate && !field.Createable:
+			c.add(fieldPath, key, ViolationNotCreateable, fmt.Sprintf("%v cannot be set on create", fieldPath))
+		}
+
+		if text, ok := value.(string); ok && field.MaxLength > 0 && utf8.RuneCountInString(text) > field.MaxLength {
+			c.add(fieldPath, key, ViolationMaxLength, fmt.Sprintf("%v is %v characters long, the limit is %v", fieldPath, utf8.RuneCountInString(text), field.MaxLength))
+		}
+	}
+
+	if mode != checkCreate {
+		return nil
+	}
+
+	for _, field := range description.Fields {
+		if !field.Required || !field.Createable {
+			continue
+		}
+
+		if value, ok := lookupField(object, field.Name); !ok || value == nil {
+			fieldPath := joinPath(prefix, field.Name)
+			c.add(fieldPath, field.Name, ViolationRequiredField, fmt.Sprintf("%v is required", fieldPath))
+		}
+	}
+
+	return nil
+}
+
+func (c *validation) add(path, field string, kind ViolationKind, message string) {
+	c.violations = append(c.violations, Violation{Path: path, Field: field, Kind: kind, Message: message})
+}
+
+// relatedObject returns the object a nested key refers to, for example Contact for BillToContact.
+func relatedObject(description *ObjectDescription, key string) (ObjecName, bool) {
+	for _, related := range description.RelatedObjects {
+		if !strings.EqualFold(related.Name, key) {
+			continue
+		}
+
+		if related.Href != "" {
+			return ObjecName(path.Base(related.Href)), true
+		}
+
+		return ObjecName(related.Name), true
+	}
+
+	return "", false
+}
+
+func lookupField(object map[string]interface{}, name string) (interface{}, bool) {
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func describePath(prefix, objectName string) string {
+	if prefix == "" {
+		return "the " + objectName + " payload"
+	}
+
+	return prefix
+}
+
+// ViolationKind is the rule a payload broke.
+type ViolationKind string
+
+const (
+	//ViolationUnknownField the field does not exist
+	ViolationUnknownField ViolationKind = "unknownField"
+	//ViolationRequiredField a required field is missing
+	ViolationRequiredField ViolationKind = "requiredField"
+	//ViolationNotCreateable the field cannot be set when creating
+	ViolationNotCreateable ViolationKind = "notCreateable"
+	//ViolationNotUpdateable the field cannot be updated
+	ViolationNotUpdateable ViolationKind = "notUpdateable"
+	//ViolationMaxLength the text is longer than the field maxlength
+	ViolationMaxLength ViolationKind = "maxLength"
+	//ViolationInvalidFormat the payload is not an object
+	ViolationInvalidFormat ViolationKind = "invalidFormat"
+)
+
+var violationSentinels = map[ViolationKind]error{
+	ViolationUnknownField:  ErrUnknownField,
+	ViolationRequiredField: ErrRequiredField,
+	ViolationNotCreateable: ErrRuleRestriction,
+	ViolationNotUpdateable: ErrRuleRestriction,
+	ViolationMaxLength:     ErrInvalidFormat,
+	ViolationInvalidFormat: ErrInvalidFormat,
+}
+
+// Violation is a single problem found in a payload. Path locates the field, such as
+// BillToContact.FirstName or [3].Name for the fourth object of a slice.
+type Violation struct {
+	Path    string        `json:"path"`
+	Field   string        `json:"field"`
+	Kind    ViolationKind `json:"kind"`
+	Message string        `json:"message"`
+}
+
+// ValidationError lists every violation found by a Validator. It matches the sentinel errors
+// Zuora would have answered with, such as ErrUnknownField or ErrRequiredField, through errors.Is.
+type ValidationError struct {
+	Object     string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+
+	return fmt.Sprintf("invalid %v payload: %v", e.Object, strings.Join(messages, "; "))
+}
+
+// Is reports whether any violation matches target, one of the sentinel errors.
+func (e *ValidationError) Is(target error) bool {
+	for _, violation := range e.Violations {
+		if violationSentinels[violation.Kind] == target {
+			return true
+		}
+	}
+
+	return false
+}
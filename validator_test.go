@@ -0,0 +1,193 @@
+package zuora
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const contactDescription = `<object>
+  <name>Contact</name>
+  <label>Contact</label>
+  <fields>
+    <field><name>FirstName</name><label>First Name</label><createable>true</createable><updateable>true</updateable><required>true</required><maxlength>5</maxlength><type>text</type></field>
+    <field><name>LastName</name><label>Last Name</label><createable>true</createable><updateable>true</updateable><required>true</required><maxlength>100</maxlength><type>text</type></field>
+  </fields>
+</object>`
+
+const subscriptionDescription = `<object>
+  <name>Subscription</name>
+  <label>Subscription</label>
+  <fields>
+    <field><name>Notes</name><label>Notes</label><createable>true</createable><updateable>true</updateable><maxlength>500</maxlength><type>text</type></field>
+    <field><name>AutoRenew</name><label>Auto Renew</label><createable>true</createable><updateable>true</updateable><type>boolean</type></field>
+  </fields>
+</object>`
+
+const ratePlanDescription = `<object>
+  <name>RatePlan</name>
+  <label>Rate Plan</label>
+  <fields>
+    <field><name>AmendmentId</name><label>Amendment ID</label><createable>true</createable><required>true</required><type>text</type></field>
+    <field><name>ProductRatePlanId</name><label>Product Rate Plan ID</label><createable>true</createable><maxlength>32</maxlength><type>text</type></field>
+  </fields>
+</object>`
+
+func testSchema(t *testing.T) Schema {
+	account, err := ParseObjectDescription([]byte(accountDescription))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contact, err := ParseObjectDescription([]byte(contactDescription))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := ParseObjectDescription([]byte(subscriptionDescription))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ratePlan, err := ParseObjectDescription([]byte(ratePlanDescription))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSchema(account, contact, subscription, ratePlan)
+}
+
+func TestValidatorReportsEveryViolation(t *testing.T) {
+	validator := NewValidator(testSchema(t), 0)
+	ctx := context.Background()
+
+	err := validator.ValidateCreate(ctx, "Account", map[string]interface{}{
+		"Region__c":     strings.Repeat("x", 51),
+		"Nickname":      "acme",
+		"billToContact": map[string]string{"firstName": "Alexandra"},
+	})
+
+	validationErr := &ValidationError{}
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ValidateCreate() = %v, want a *ValidationError", err)
+	}
+
+	want := []Violation{
+		{Path: "Nickname", Field: "Nickname", Kind: ViolationUnknownField, Message: "Nickname is not a field of Account"},
+		{Path: "Region__c", Field: "Region__c", Kind: ViolationMaxLength, Message: "Region__c is 51 characters long, the limit is 50"},
+		{Path: "billToContact.firstName", Field: "firstName", Kind: ViolationMaxLength, Message: "billToContact.firstName is 9 characters long, the limit is 5"},
+		{Path: "billToContact.LastName", Field: "LastName", Kind: ViolationRequiredField, Message: "billToContact.LastName is required"},
+		{Path: "Name", Field: "Name", Kind: ViolationRequiredField, Message: "Name is required"},
+	}
+
+	if !reflect.DeepEqual(validationErr.Violations, want) {
+		t.Errorf("ValidateCreate() violations = %+v, want %+v", validationErr.Violations, want)
+	}
+
+	if !errors.Is(err, ErrUnknownField) || !errors.Is(err, ErrRequiredField) || !errors.Is(err, ErrInvalidFormat) || errors.Is(err, ErrNotFound) {
+		t.Errorf("ValidateCreate() = %v should match the sentinels of its violations only", err)
+	}
+
+	err = validator.ValidateUpdate(ctx, "Account", []map[string]string{{"Id": "A1", "name": "Acme"}, {"Id": "A2", "Region__c": "EMEA"}})
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 || validationErr.Violations[0].Path != "[1].Region__c" || validationErr.Violations[0].Kind != ViolationNotUpdateable {
+		t.Errorf("ValidateUpdate() = %v", err)
+	}
+
+	if err := validator.ValidateCreate(ctx, "Account", map[string]string{"Name": "Acme", "Status": "Draft"}); err != nil {
+		t.Errorf("ValidateCreate() = %v, want no violation", err)
+	}
+
+	if err := validator.ValidateCreate(ctx, "Nope", map[string]string{"Name": "Acme"}); err == nil {
+		t.Errorf("ValidateCreate() should fail for objects without metadata")
+	}
+}
+
+func TestValidatorCachesDescriptions(t *testing.T) {
+	describes := 0
+	creates := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/describe/Account":
+			describes++
+			rw.Write([]byte(accountDescription))
+		case "/v1/action/create":
+			creates++
+			rw.Write([]byte(`[{"Success": true, "Id": "A1"}]`))
+		default:
+			t.Errorf("unexpected request to %v", req.URL)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	describer := newTestAPI(mockServer).V1.DescribeService
+	api := newTestAPI(mockServer, WithValidator(NewValidator(describer, time.Hour)))
+	ctx := context.Background()
+
+	if _, err := api.V1.ActionsService.CreateObjects(ctx, "Account", []map[string]string{{"Nickname": "acme"}}, false); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("ActionsService.CreateObjects() = %v, want ErrUnknownField", err)
+	}
+
+	if _, err := api.V1.ActionsService.Create(ctx, map[string]interface{}{"type": "Account", "objects": []map[string]string{{}}}, false); !errors.Is(err, ErrRequiredField) {
+		t.Errorf("ActionsService.Create() = %v, want ErrRequiredField", err)
+	}
+
+	if results, err := api.V1.ActionsService.CreateObjects(ctx, "Account", []map[string]string{{"Name": "Acme"}}, false); err != nil || len(results) != 1 {
+		t.Errorf("ActionsService.CreateObjects() = %+v, %v", results, err)
+	}
+
+	if describes != 1 || creates != 1 {
+		t.Errorf("described Account %v times and created %v times, want 1 and 1", describes, creates)
+	}
+}
+
+func TestValidatorChecksRESTUpdates(t *testing.T) {
+	updates := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		updates++
+		rw.Write([]byte(`{"success": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer, WithValidator(NewValidator(testSchema(t), 0)))
+	ctx := context.Background()
+
+	subscriptionUpdate := map[string]interface{}{
+		"notes":             "Upgrade",
+		"autoRenew":         true,
+		"invoiceSeparately": true,
+		"collect":           false,
+		"add":               []map[string]string{{"productRatePlanId": strings.Repeat("P", 40), "contractEffectiveDate": "2020-01-01"}},
+		"remove":            []map[string]string{{"ratePlanId": "R2", "contractEffectiveDate": "2020-01-01"}},
+	}
+
+	_, err := api.V1.SubscriptionsService.Update(ctx, "A-S0001", subscriptionUpdate)
+	validationErr := &ValidationError{}
+	want := []Violation{{Path: "add[0].productRatePlanId", Field: "productRatePlanId", Kind: ViolationMaxLength, Message: "add[0].productRatePlanId is 40 characters long, the limit is 32"}}
+
+	if !errors.As(err, &validationErr) || !reflect.DeepEqual(validationErr.Violations, want) {
+		t.Errorf("SubscriptionsService.Update() = %v, want %+v", err, want)
+	}
+
+	subscriptionUpdate["add"] = []map[string]string{{"productRatePlanId": "P1", "contractEffectiveDate": "2020-01-01"}}
+	if _, err := api.V1.SubscriptionsService.Update(ctx, "A-S0001", subscriptionUpdate); err != nil {
+		t.Errorf("SubscriptionsService.Update() = %v, want no violation", err)
+	}
+
+	if _, err := api.V1.AccountsService.Update(ctx, "A0001", map[string]string{"salesRep": "Jane", "Region__c": "EMEA"}); !errors.Is(err, ErrRuleRestriction) {
+		t.Errorf("AccountsService.Update() = %v, want ErrRuleRestriction", err)
+	}
+
+	if _, err := api.V1.AccountsService.Update(ctx, "A0001", map[string]string{"name": "Acme", "salesRep": "Jane"}); err != nil {
+		t.Errorf("AccountsService.Update() = %v, want no violation", err)
+	}
+
+	if updates != 2 {
+		t.Errorf("sent %v updates, want only the 2 valid ones", updates)
+	}
+}
@@ -0,0 +1,279 @@
+package zuora
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ZOQL is a raw ZOQL query, for queries the QueryBuilder cannot express.
+// Values pasted into it are not escaped, prefer Select whenever possible.
+type ZOQL string
+
+// Build returns the query.
+func (z ZOQL) Build() string {
+	return strings.TrimSpace(string(z))
+}
+
+// buildQuery returns the query built by querier, refusing the ones a QueryBuilder reported an error for.
+func buildQuery(querier Querier) (string, error) {
+	if builder, ok := querier.(interface{ Err() error }); ok {
+		if err := builder.Err(); err != nil {
+			return "", responseError{isTemporary: false, message: fmt.Sprintf("error while trying to build the query: %v", err), err: err}
+		}
+	}
+
+	return strings.TrimSpace(querier.Build()), nil
+}
+
+// Date is a date without time, written in ZOQL as '2006-01-02'.
+// Pass a time.Time instead to compare against a date and time.
+// It is also read and written as "2006-01-02" in JSON and CSV, as Zuora sends dates.
+type Date time.Time
+
+// String returns the date as 2006-01-02.
+func (d Date) String() string {
+	return time.Time(d).Format("2006-01-02")
+}
+
+// MarshalText writes the date as 2006-01-02.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads a date written as 2006-01-02.
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse("2006-01-02", string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*d = Date(t)
+	return nil
+}
+
+// zoqlIdentifier matches object and field names, including custom fields such as Region__c
+// and the joined fields of export queries such as Account.Name.
+var zoqlIdentifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)?$`)
+
+// QueryBuilder builds ZOQL queries, quoting and escaping every value so they can be
+// built from user input safely. Keywords are written in lower case, as ZOQL requires.
+// Create one with Select:
+//
+//	query := zuora.Select("Id", "Amount").
+//		From("Invoice").
+//		Where(zuora.Gte("InvoiceDate", zuora.Date(lastYear))).
+//		And(zuora.Eq("Status", "Posted"))
+//
+// The first invalid object name, field name or value is reported by Err, and
+// ActionsService.Query refuses to send the query.
+type QueryBuilder struct {
+	fields     []string
+	object     string
+	conditions []clause
+	limit      int
+	err        error
+}
+
+// Select starts a query retrieving fields.
+func Select(fields ...string) *QueryBuilder {
+	q := &QueryBuilder{}
+
+	for _, field := range fields {
+		q.fields = append(q.fields, q.identifier(field))
+	}
+
+	return q
+}
+
+// From sets the object queried, such as Account or Invoice.
+func (q *QueryBuilder) From(object string) *QueryBuilder {
+	q.object = q.identifier(object)
+	return q
+}
+
+// Where filters the records returned. It is the same as And.
+func (q *QueryBuilder) Where(condition Condition) *QueryBuilder {
+	return q.And(condition)
+}
+
+// And adds a condition every record must match.
+func (q *QueryBuilder) And(condition Condition) *QueryBuilder {
+	return q.condition("and", condition)
+}
+
+// Or adds a condition records may match instead of the previous ones.
+func (q *QueryBuilder) Or(condition Condition) *QueryBuilder {
+	return q.condition("or", condition)
+}
+
+// Limit caps the number of records read to limit. QueryAll stops once limit records were read,
+// and every call asks Zuora for no more than that, up to the 2000 records of a single call.
+// Zero reads every record.
+func (q *QueryBuilder) Limit(limit int) *QueryBuilder {
+	if limit < 0 && q.err == nil {
+		q.err = fmt.Errorf("zoql: limit %v must not be negative", limit)
+	}
+
+	q.limit = limit
+	return q
+}
+
+// BatchSize returns the number of records a single call asks for: the limit, up to 2000.
+func (q *QueryBuilder) BatchSize() int {
+	if q.limit > maxQueryBatchSize {
+		return maxQueryBatchSize
+	}
+
+	return q.limit
+}
+
+// maxQueryBatchSize is the number of records a single query call returns at most.
+const maxQueryBatchSize = 2000
+
+func (q *QueryBuilder) recordLimit() int {
+	return q.limit
+}
+
+// Err returns the first error found while building the query.
+func (q *QueryBuilder) Err() error {
+	if q.err == nil && len(q.fields) == 0 {
+		return fmt.Errorf("zoql: a query must select at least one field")
+	}
+
+	if q.err == nil && q.object == "" {
+		return fmt.Errorf("zoql: a query must select from an object")
+	}
+
+	return q.err
+}
+
+// Build returns the ZOQL query.
+func (q *QueryBuilder) Build() string {
+	var builder strings.Builder
+
+	builder.WriteString("select ")
+	builder.WriteString(strings.Join(q.fields, ", "))
+	builder.WriteString(" from ")
+	builder.WriteString(q.object)
+
+	for i, clause := range q.conditions {
+		keyword := clause.keyword
+		if i == 0 {
+			keyword = "where"
+		}
+
+		fmt.Fprintf(&builder, " %v %v", keyword, clause.condition)
+	}
+
+	return builder.String()
+}
+
+func (q *QueryBuilder) condition(keyword string, condition Condition) *QueryBuilder {
+	field := q.identifier(condition.field)
+	value, err := zoqlValue(condition.value)
+
+	if err != nil && q.err == nil {
+		q.err = err
+	}
+
+	q.conditions = append(q.conditions, clause{keyword: keyword, condition: fmt.Sprintf("%v %v %v", field, condition.operator, value)})
+	return q
+}
+
+// clause is a condition of the where clause and the keyword joining it to the previous one.
+type clause struct {
+	keyword   string
+	condition string
+}
+
+func (q *QueryBuilder) identifier(name string) string {
+	if !zoqlIdentifier.MatchString(name) && q.err == nil {
+		q.err = fmt.Errorf("zoql: invalid name %q", name)
+	}
+
+	return name
+}
+
+// Condition compares a field to a value in a ZOQL where clause.
+type Condition struct {
+	field    string
+	operator string
+	value    interface{}
+}
+
+// Eq matches records where field equals value.
+func Eq(field string, value interface{}) Condition {
+	return Condition{field: field, operator: "=", value: value}
+}
+
+// NotEq matches records where field is different from value.
+func NotEq(field string, value interface{}) Condition {
+	return Condition{field: field, operator: "!=", value: value}
+}
+
+// Gt matches records where field is greater than value.
+func Gt(field string, value interface{}) Condition {
+	return Condition{field: field, operator: ">", value: value}
+}
+
+// Gte matches records where field is greater than or equal to value.
+func Gte(field string, value interface{}) Condition {
+	return Condition{field: field, operator: ">=", value: value}
+}
+
+// Lt matches records where field is less than value.
+func Lt(field string, value interface{}) Condition {
+	return Condition{field: field, operator: "<", value: value}
+}
+
+// Lte matches records where field is less than or equal to value.
+func Lte(field string, value interface{}) Condition {
+	return Condition{field: field, operator: "<=", value: value}
+}
+
+// Like matches records where field matches pattern, in which % matches any characters.
+func Like(field string, pattern string) Condition {
+	return Condition{field: field, operator: "like", value: pattern}
+}
+
+// IsNull matches records where field is not set.
+func IsNull(field string) Condition {
+	return Condition{field: field, operator: "=", value: nil}
+}
+
+// IsNotNull matches records where field is set.
+func IsNotNull(field string) Condition {
+	return Condition{field: field, operator: "!=", value: nil}
+}
+
+var zoqlEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// zoqlValue writes value as a ZOQL literal.
+func zoqlValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return "'" + zoqlEscaper.Replace(v) + "'", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case Date:
+		return "'" + v.String() + "'", nil
+	case time.Time:
+		return "'" + v.Format("2006-01-02T15:04:05-07:00") + "'", nil
+	case fmt.Stringer:
+		return zoqlValue(v.String())
+	}
+
+	return "", fmt.Errorf("zoql: unsupported value %v of type %T", value, value)
+}
@@ -0,0 +1,210 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// zoqlField is a struct field selected in a query.
+type zoqlField struct {
+	name  string
+	index []int
+}
+
+// SelectFor starts a query selecting the fields of a struct, so the query and the struct
+// decoding its records cannot drift apart. model is a struct, a slice of structs, or a pointer
+// to either. Each exported field is selected by the name in its zoql tag, its json tag or,
+// without tags, its Go name. Fields tagged zoql:"-" are skipped. The fields of embedded structs,
+// or of pointers to exported structs which are allocated when decoding, are selected too.
+//
+//	type Invoice struct {
+//		ID     string  `json:"Id"`
+//		Amount float64 `json:"Amount"`
+//		Region string  `zoql:"Region__c"`
+//	}
+//
+//	invoices := []Invoice{}
+//	query := zuora.SelectFor(&invoices).From("Invoice")
+//	err := zuoraAPI.V1.ActionsService.QueryInto(ctx, query, &invoices)
+func SelectFor(model interface{}) *QueryBuilder {
+	fields, err := zoqlFields(reflect.TypeOf(model))
+
+	if err != nil {
+		return &QueryBuilder{err: err}
+	}
+
+	names := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		names = append(names, field.name)
+	}
+
+	return Select(names...)
+}
+
+// QueryInto runs the query and appends every record to out, a pointer to a slice of structs
+// or of pointers to structs, following the query locator past the 2000 records limit.
+// Struct fields are filled by the same names SelectFor selects.
+func (t *actionsService) QueryInto(ctx context.Context, querier Querier, out interface{}) error {
+	slice := reflect.ValueOf(out)
+
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return responseError{isTemporary: false, message: fmt.Sprintf("QueryInto needs a pointer to a slice, got %T", out)}
+	}
+
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	fields, err := zoqlFields(elemType)
+
+	if err != nil {
+		return responseError{isTemporary: false, message: err.Error(), err: err}
+	}
+
+	records := t.QueryAll(ctx, querier)
+
+	for records.Next() {
+		elem := reflect.New(structType(elemType))
+
+		if err := decodeRecord(records.current, elem.Elem(), fields); err != nil {
+			return err
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	return records.Err()
+}
+
+// decodeRecord sets the fields of v from record, matching names without regard to case
+// since Zuora capitalizes field names in its responses.
+func decodeRecord(record json.RawMessage, v reflect.Value, fields []zoqlField) error {
+	values := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(record, &values); err != nil {
+		return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal query record. Error: %v. JSON: %v", err, string(record)), err: err}
+	}
+
+	for name, value := range values {
+		values[strings.ToLower(name)] = value
+	}
+
+	for _, field := range fields {
+		value, ok := values[strings.ToLower(field.name)]
+
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(value, fieldByIndex(v, field.index).Addr().Interface()); err != nil {
+			return responseError{isTemporary: false, message: fmt.Sprintf("error while Unmarshal query field %v. Error: %v. JSON: %v", field.name, err, string(value)), err: err}
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex returns the field of v at index, allocating the embedded struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// structType unwraps pointers and slices down to the struct type.
+func structType(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func zoqlFields(t reflect.Type) ([]zoqlField, error) {
+	t = structType(t)
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("zoql: %v is not a struct", t)
+	}
+
+	fields := []zoqlField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && isEmbeddedStruct(field.Type) {
+			// Pointers to unexported structs cannot be allocated when decoding.
+			if field.Type.Kind() == reflect.Ptr && field.PkgPath != "" {
+				return nil, fmt.Errorf("zoql: embedded field %v of %v is a pointer to an unexported struct", field.Name, t)
+			}
+
+			embedded, err := zoqlFields(field.Type)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, e := range embedded {
+				fields = append(fields, zoqlField{name: e.name, index: append([]int{i}, e.index...)})
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := zoqlFieldName(field)
+
+		if name == "" {
+			continue
+		}
+
+		fields = append(fields, zoqlField{name: name, index: []int{i}})
+	}
+
+	return fields, nil
+}
+
+func isEmbeddedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct)
+}
+
+func zoqlFieldName(field reflect.StructField) string {
+	for _, key := range []string{"zoql", "json"} {
+		tag, ok := field.Tag.Lookup(key)
+
+		if !ok {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		if name == "-" {
+			return ""
+		}
+
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
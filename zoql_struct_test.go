@@ -0,0 +1,123 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type zoqlTestBase struct {
+	ID string `json:"Id"`
+}
+
+type zoqlTestInvoice struct {
+	zoqlTestBase
+	Amount   float64 `json:"Amount,omitempty"`
+	Region   string  `zoql:"Region__c"`
+	Status   string
+	Ignored  string `zoql:"-"`
+	internal string
+}
+
+// ZoqlTestAudit is exported so it can be embedded as a pointer and allocated when decoding.
+type ZoqlTestAudit struct {
+	CreatedByID string `json:"CreatedById"`
+}
+
+type zoqlTestCredit struct {
+	*ZoqlTestAudit
+	Amount float64
+}
+
+type zoqlTestHidden struct {
+	*zoqlTestBase
+}
+
+func TestSelectFor(t *testing.T) {
+	want := "select Id, Amount, Region__c, Status from Invoice"
+
+	for _, model := range []interface{}{zoqlTestInvoice{}, &zoqlTestInvoice{}, []zoqlTestInvoice{}, &[]*zoqlTestInvoice{}} {
+		query := SelectFor(model).From("Invoice")
+
+		if err := query.Err(); err != nil {
+			t.Errorf("SelectFor(%T) returned an error: %v", model, err)
+		}
+
+		if got := query.Build(); got != want {
+			t.Errorf("SelectFor(%T) = %q, want %q", model, got, want)
+		}
+	}
+
+	if err := SelectFor("invoice").From("Invoice").Err(); err == nil {
+		t.Error("SelectFor(string) wanted an error but got nil")
+	}
+
+	if err := SelectFor(zoqlTestHidden{}).From("Invoice").Err(); err == nil {
+		t.Error("SelectFor(zoqlTestHidden) wanted an error for its embedded pointer but got nil")
+	}
+}
+
+func TestEmbeddedStructPointers(t *testing.T) {
+	if got, want := SelectFor(zoqlTestCredit{}).From("CreditMemo").Build(), "select CreatedById, Amount from CreditMemo"; got != want {
+		t.Errorf("SelectFor(zoqlTestCredit) = %q, want %q", got, want)
+	}
+
+	fields, err := zoqlFields(reflect.TypeOf(zoqlTestCredit{}))
+	if err != nil {
+		t.Fatalf("zoqlFields() returned an error: %v", err)
+	}
+
+	credit := zoqlTestCredit{}
+	if err := decodeRecord(json.RawMessage(`{"CreatedById": "U1", "Amount": 5}`), reflect.ValueOf(&credit).Elem(), fields); err != nil || credit.ZoqlTestAudit == nil || credit.CreatedByID != "U1" || credit.Amount != 5 {
+		t.Errorf("decodeRecord() = %+v, %v", credit, err)
+	}
+
+	rows := NewCSVDecoder(strings.NewReader("CreatedById,Amount\nU2,7\n"))
+	credits := []zoqlTestCredit{}
+	if err := rows.DecodeAll(&credits); err != nil || len(credits) != 1 || credits[0].ZoqlTestAudit == nil || credits[0].CreatedByID != "U2" {
+		t.Errorf("CSVDecoder.DecodeAll() = %+v, %v", credits, err)
+	}
+}
+
+func TestQueryInto(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload := queryRequest{}
+		json.NewDecoder(req.Body).Decode(&payload)
+		rw.WriteHeader(200)
+
+		if req.URL.Path == "/v1/action/query" {
+			if want := "select Id, Amount, Region__c, Status from Invoice"; payload.QueryString != want {
+				t.Errorf("queryString = %q, want %q", payload.QueryString, want)
+			}
+
+			rw.Write([]byte(`{"records": [{"Id": "1", "Amount": 10.5, "Region__c": "EU", "Status": "Posted"}], "size": 2, "done": false, "queryLocator": "locator"}`))
+			return
+		}
+
+		rw.Write([]byte(`{"records": [{"Id": "2", "Amount": 3, "region__c": "US"}], "size": 2, "done": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	invoices := []*zoqlTestInvoice{}
+
+	if err := api.V1.ActionsService.QueryInto(context.Background(), SelectFor(&invoices).From("Invoice"), &invoices); err != nil {
+		t.Fatalf("ActionsService.QueryInto() returned an error: %v", err)
+	}
+
+	if len(invoices) != 2 {
+		t.Fatalf("ActionsService.QueryInto() decoded %v invoices, want 2", len(invoices))
+	}
+
+	if got := *invoices[0]; got.ID != "1" || got.Amount != 10.5 || got.Region != "EU" || got.Status != "Posted" {
+		t.Errorf("ActionsService.QueryInto() first invoice = %+v", got)
+	}
+
+	if got := *invoices[1]; got.ID != "2" || got.Region != "US" {
+		t.Errorf("ActionsService.QueryInto() second invoice = %+v", got)
+	}
+}
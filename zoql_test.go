@@ -0,0 +1,214 @@
+package zuora
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	date := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tt := range []struct {
+		query *QueryBuilder
+		want  string
+	}{
+		{
+			Select("Id", "Name").From("Account"),
+			"select Id, Name from Account",
+		},
+		{
+			Select("Id").From("Account").Where(Eq("Name", `O'Brien "\ Co`)).And(NotEq("Balance", 0)).Or(Gte("Amount", 10.5)),
+			`select Id from Account where Name = 'O\'Brien "\\ Co' and Balance != 0 or Amount >= 10.5`,
+		},
+		{
+			Select("Id").From("Invoice").Where(Gt("InvoiceDate", Date(date))).And(Lte("UpdatedDate", date)).And(Lt("Count__c", 3)),
+			"select Id from Invoice where InvoiceDate > '2019-01-02' and UpdatedDate <= '2019-01-02T03:04:05+00:00' and Count__c < 3",
+		},
+		{
+			Select("Id").From("Account").Where(IsNull("Batch")).Or(IsNotNull("ParentId")).And(Like("Name", "Acme%")).And(Eq("AutoPay", true)),
+			"select Id from Account where Batch = null or ParentId != null and Name like 'Acme%' and AutoPay = true",
+		},
+	} {
+		if err := tt.query.Err(); err != nil {
+			t.Errorf("QueryBuilder.Err() = %v, want nil", err)
+		}
+
+		if got := tt.query.Build(); got != tt.want {
+			t.Errorf("QueryBuilder.Build() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestQueryBuilderErrors(t *testing.T) {
+	for _, query := range []*QueryBuilder{
+		Select("Id; delete").From("Account"),
+		Select("Id").From("Account where 1=1"),
+		Select("Id").From("Account").Where(Eq("Name", []string{"a"})),
+		Select("Id").From("Account").Limit(-1),
+		Select().From("Account"),
+		Select("Id"),
+	} {
+		if query.Err() == nil {
+			t.Errorf("QueryBuilder.Err() for %q wanted an error but got nil", query.Build())
+		}
+	}
+}
+
+func TestQueryWithBuilder(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		want := `{"queryString":"select Id from Account","conf":{"batchSize":100}}`
+
+		if string(body) != want {
+			t.Errorf("request body = %s, want %s", body, want)
+		}
+
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"records": [], "size": 0, "done": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+
+	if _, err := api.V1.ActionsService.Query(context.Background(), Select("Id").From("Account").Limit(100)); err != nil {
+		t.Errorf("ActionsService.Query() returned an error: %v", err)
+	}
+
+	if _, err := api.V1.ActionsService.Query(context.Background(), Select("Id").From("Account; drop")); err == nil {
+		t.Error("ActionsService.Query() wanted an error for an invalid query but got nil")
+	}
+}
+
+func TestQueryEscapesAndDecodes(t *testing.T) {
+	zoql := "select Id from Account where Name = 'say \"hi\"' and Notes = 'a\\\\b\nc'"
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload := queryRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Errorf("request body is not valid JSON: %v", err)
+		}
+
+		if payload.QueryString != zoql {
+			t.Errorf("queryString = %q, want %q", payload.QueryString, zoql)
+		}
+
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"records": [{"Id": "1", "Balance": 10.5}, {"Id": "2", "Balance": 0}], "size": 2, "done": false, "queryLocator": "locator"}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	result, err := api.V1.ActionsService.Query(context.Background(), ZOQL(zoql))
+
+	if err != nil {
+		t.Fatalf("ActionsService.Query() returned an error: %v", err)
+	}
+
+	if result.Size != 2 || result.Done || result.QueryLocator != "locator" {
+		t.Errorf("ActionsService.Query() = %+v, want size 2, not done and locator", result)
+	}
+
+	accounts := []struct {
+		ID      string  `json:"Id"`
+		Balance float64 `json:"Balance"`
+	}{}
+
+	if err := result.Decode(&accounts); err != nil || len(accounts) != 2 || accounts[0].ID != "1" || accounts[0].Balance != 10.5 {
+		t.Errorf("QueryResult.Decode() = %+v, %v", accounts, err)
+	}
+
+	maps, err := result.Maps()
+	if err != nil || len(maps) != 2 || maps[1]["Id"] != "2" {
+		t.Errorf("QueryResult.Maps() = %v, %v", maps, err)
+	}
+}
+
+func TestQueryAllFollowsQueryLocator(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.URL.Path)
+		rw.WriteHeader(200)
+
+		if req.URL.Path == "/v1/action/query" {
+			rw.Write([]byte(`{"records": [{"Id": "1"}, {"Id": "2"}], "size": 3, "done": false, "queryLocator": "locator"}`))
+			return
+		}
+
+		payload := queryMoreRequest{}
+		json.NewDecoder(req.Body).Decode(&payload)
+
+		if payload.QueryLocator != "locator" || payload.Conf != nil {
+			t.Errorf("queryMore body = %+v, want locator and no batch size", payload)
+		}
+
+		rw.Write([]byte(`{"records": [{"Id": "3"}], "size": 3, "done": true}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	query := Select("Id").From("Account")
+	records := api.V1.ActionsService.QueryAll(context.Background(), query)
+	ids := ""
+
+	for records.Next() {
+		record := struct {
+			ID string `json:"Id"`
+		}{}
+
+		if err := records.Decode(&record); err != nil {
+			t.Errorf("QueryIterator.Decode() returned an error: %v", err)
+		}
+
+		ids += record.ID
+	}
+
+	if err := records.Err(); err != nil || ids != "123" || len(requests) != 2 {
+		t.Errorf("QueryAll() read %q in %v requests with error %v, want 123 in 2 requests", ids, requests, err)
+	}
+
+	requests = nil
+	records = api.V1.ActionsService.QueryAll(context.Background(), query)
+
+	if !records.Next() || len(requests) != 1 {
+		t.Errorf("QueryAll() sent %v requests to read the first record, want 1", requests)
+	}
+}
+
+func TestQueryAllStopsAtLimit(t *testing.T) {
+	var batchSizes []int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload := queryMoreRequest{}
+		json.NewDecoder(req.Body).Decode(&payload)
+		batchSizes = append(batchSizes, payload.Conf.BatchSize)
+		rw.WriteHeader(200)
+
+		if req.URL.Path == "/v1/action/query" {
+			rw.Write([]byte(`{"records": [{"Id": "1"}, {"Id": "2"}], "size": 5, "done": false, "queryLocator": "locator"}`))
+			return
+		}
+
+		rw.Write([]byte(`{"records": [{"Id": "3"}, {"Id": "4"}], "size": 5, "done": false, "queryLocator": "locator"}`))
+	}))
+	defer mockServer.Close()
+
+	api := newTestAPI(mockServer)
+	records := api.V1.ActionsService.QueryAll(context.Background(), Select("Id").From("Account").Limit(3))
+	read := 0
+
+	for records.Next() {
+		read++
+	}
+
+	if err := records.Err(); err != nil || read != 3 || !reflect.DeepEqual(batchSizes, []int{3, 1}) {
+		t.Errorf("QueryAll() read %v records asking for %v with error %v, want 3 records asking for [3 1]", read, batchSizes, err)
+	}
+
+	if size := Select("Id").From("Account").Limit(5000).BatchSize(); size != 2000 {
+		t.Errorf("QueryBuilder.BatchSize() = %v, want 2000", size)
+	}
+}